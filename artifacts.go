@@ -0,0 +1,63 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// maxArtifacts is how many generated files are named after a run
+const maxArtifacts = 5
+
+// generatedFiles returns the modification times of the files matching the
+// task's generates globs, which are relative to the directory the task runs
+// in. Templated globs are skipped, and ** matches a single directory, as
+// filepath.Glob does.
+func generatedFiles(task Task) fileTimes {
+	files := fileTimes{}
+	for _, pattern := range task.Generates {
+		if isTemplated(pattern) {
+			continue
+		}
+		if !filepath.IsAbs(pattern) {
+			pattern = filepath.Join(task.WorkDir, pattern)
+		}
+		matches, _ := filepath.Glob(strings.ReplaceAll(pattern, "**", "*"))
+		for _, match := range matches {
+			if info, err := os.Stat(match); err == nil && !info.IsDir() {
+				files[match] = info.ModTime()
+			}
+		}
+	}
+	return files
+}
+
+// generatedSince returns the task's generated files that were created or
+// modified since before was taken, relative to the task's directory
+func generatedSince(task Task, before fileTimes) []string {
+	var changed []string
+	for file, t := range generatedFiles(task) {
+		if old, ok := before[file]; ok && old.Equal(t) {
+			continue
+		}
+		if rel, err := filepath.Rel(task.WorkDir, file); err == nil {
+			file = rel
+		}
+		changed = append(changed, file)
+	}
+	slices.Sort(changed)
+	return changed
+}
+
+// artifactsNote lists the files a run generated, to add to its status
+func artifactsNote(files []string) string {
+	if len(files) == 0 {
+		return ""
+	}
+	if len(files) > maxArtifacts {
+		return fmt.Sprintf(" • generated %s and %d more", strings.Join(files[:maxArtifacts], ", "), len(files)-maxArtifacts)
+	}
+	return " • generated " + strings.Join(files, ", ")
+}
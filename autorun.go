@@ -0,0 +1,69 @@
+package main
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// autoRunDelay is how long the filter has to match a single task before it
+// is run, leaving time to keep typing
+const autoRunDelay = 600 * time.Millisecond
+
+// defaultAutoRunExclude keeps tasks whose names suggest they deploy,
+// publish or delete things from being run by a few keystrokes
+var defaultAutoRunExclude = []string{
+	"*deploy*", "*release*", "*publish*", "*push*",
+	"*clean*", "*delete*", "*destroy*", "*drop*",
+	"*prune*", "*purge*", "*reset*", "*uninstall*",
+}
+
+// autoRun runs the only task the filter matches, after a pause, when
+// enabled with --auto-run or the auto_run config option
+var autoRun bool
+
+// autoRunMsg fires once the filter has matched a single task for a while
+type autoRunMsg struct {
+	filter string // Filter when the run was scheduled
+}
+
+// scheduleAutoRun schedules running the task the filter narrowed down to,
+// unless auto-run is off or the task matches an auto_run_exclude pattern
+func (m *model) scheduleAutoRun() tea.Cmd {
+	task, ok := m.autoRunTask()
+	if !ok {
+		return nil
+	}
+	m.status = "running " + task.Name + " in a moment, keep typing to cancel"
+
+	filter := m.filter.Value()
+	return tea.Tick(autoRunDelay, func(time.Time) tea.Msg {
+		return autoRunMsg{filter: filter}
+	})
+}
+
+// autoRunTask returns the task auto-run would run now, if any
+func (m model) autoRunTask() (Task, bool) {
+	if !autoRun && !config.AutoRun {
+		return Task{}, false
+	}
+	if m.filter.Value() == "" || len(m.filteredList) != 1 || m.run != nil || m.selected {
+		return Task{}, false
+	}
+
+	task := m.filteredList[0].(Task)
+	if kept, err := excludeTasks([]Task{task}, config.AutoRunExclude); err != nil || len(kept) == 0 {
+		return Task{}, false
+	}
+	return task, true
+}
+
+// autoRunDue runs the task if the filter still matches only it
+func (m model) autoRunDue(msg autoRunMsg) (tea.Model, tea.Cmd) {
+	task, ok := m.autoRunTask()
+	if !ok || msg.filter != m.filter.Value() {
+		return m, nil
+	}
+	m.status = ""
+	return m.execTask(task)
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// backgroundLinger is how long a finished background run stays listed
+const backgroundLinger = 5 * time.Second
+
+// bgRun is a task started with b, running while the list stays usable
+type bgRun struct {
+	task    Task
+	cmd     *exec.Cmd
+	before  fileTimes // The task's generated files before it ran
+	started time.Time
+	done    bool
+	err     error
+	elapsed time.Duration
+}
+
+// backgroundDoneMsg is sent when a background run has exited
+type backgroundDoneMsg struct {
+	run *bgRun
+	err error
+}
+
+// backgroundTickMsg refreshes the elapsed times of the background runs
+type backgroundTickMsg struct{}
+
+// backgroundTick schedules the next refresh of the background runs
+func backgroundTick() tea.Cmd {
+	return tea.Tick(time.Second, func(time.Time) tea.Msg {
+		return backgroundTickMsg{}
+	})
+}
+
+// runBackground starts the selected task without leaving the list. Its
+// output is discarded; only how it ended is shown.
+func (m model) runBackground() (tea.Model, tea.Cmd) {
+	if safeMode {
+		return m.refuseInSafeMode()
+	}
+	task, ok := m.list.SelectedItem().(Task)
+	if !ok || len(m.filteredList) == 0 {
+		return m, nil
+	}
+
+	selectRun(task.Name)
+	before := generatedFiles(task)
+	cmd := taskCmd.command(task.Taskfile, taskRunArgs(m.runOptions(), task.Name)...)
+	if err := cmd.Start(); err != nil {
+		m.status = fmt.Sprintf("✗ %s failed to start: %v", task.Name, err)
+		return m, nil
+	}
+	startRun(task.Name, nil)
+
+	run := &bgRun{task: task, cmd: cmd, before: before, started: time.Now()}
+	wait := func() tea.Msg {
+		return backgroundDoneMsg{run: run, err: cmd.Wait()}
+	}
+
+	// Only one tick chain runs, started when the first run is added
+	ticking := len(m.background) > 0
+	m.background = append(m.background, run)
+	if ticking {
+		return m, wait
+	}
+	m.list.SetSize(m.width, m.height-m.chromeHeight())
+	return m, tea.Batch(wait, backgroundTick())
+}
+
+// backgroundDone records how a background run ended
+func (m model) backgroundDone(msg backgroundDoneMsg) (tea.Model, tea.Cmd) {
+	run := msg.run
+	run.done = true
+	run.err = msg.err
+	run.elapsed = time.Since(run.started)
+	finishRun(run.task.Name, nil, msg.err, run.elapsed)
+	m.markRan(run.task)
+	m.status = resultStatus(run.task.Name, run.err, run.elapsed)
+	if run.err == nil {
+		m.status += artifactsNote(generatedSince(run.task, run.before))
+	}
+	return m, nil
+}
+
+// backgroundTicked drops the runs that finished a while ago and keeps
+// ticking while any are listed
+func (m model) backgroundTicked() (tea.Model, tea.Cmd) {
+	var kept []*bgRun
+	for _, run := range m.background {
+		if !run.done || time.Since(run.started.Add(run.elapsed)) < backgroundLinger {
+			kept = append(kept, run)
+		}
+	}
+	m.background = kept
+	if len(m.background) == 0 {
+		m.list.SetSize(m.width, m.height-m.chromeHeight())
+		return m, nil
+	}
+	return m, backgroundTick()
+}
+
+// backgroundHeight is the number of lines the background runs take up
+func (m model) backgroundHeight() int {
+	if len(m.background) == 0 {
+		return 0
+	}
+	return 1
+}
+
+// backgroundView lists the background runs with how long they have been
+// running, or how they ended
+func (m model) backgroundView() string {
+	var runs []string
+	for _, run := range m.background {
+		switch {
+		case !run.done:
+			runs = append(runs, fmt.Sprintf("⏵ %s %s", run.task.Name, time.Since(run.started).Truncate(time.Second)))
+		case run.err != nil:
+			runs = append(runs, fmt.Sprintf("✗ %s %s", run.task.Name, formatDuration(run.elapsed)))
+		default:
+			runs = append(runs, fmt.Sprintf("✓ %s %s", run.task.Name, formatDuration(run.elapsed)))
+		}
+	}
+	return m.currentTheme().mutedStyle().Render("background: " + strings.Join(runs, " • "))
+}
@@ -0,0 +1,263 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// echoTasks prints a banner before each task run from the command line,
+// running the tasks one by one
+var echoTasks bool
+
+// continueOnError runs every task of a batch even when one of them fails,
+// instead of stopping at the first failure
+var continueOnError bool
+
+// batchResult is the outcome of one task in a batch
+type batchResult struct {
+	name   string
+	failed bool
+}
+
+// batchFinishedMsg is sent when the tasks marked in the TUI have been run
+type batchFinishedMsg struct {
+	results []batchResult
+	tasks   []Task // The tasks of the batch, the first len(results) of which ran
+}
+
+// taskBatch runs several tasks one after the other in the foreground. It
+// implements tea.ExecCommand so the whole batch runs in a single hand-over
+// of the terminal.
+type taskBatch struct {
+	tasks   []Task
+	opts    runOptions // Flags passed to task for each run
+	results []batchResult
+	stdin   io.Reader
+	stdout  io.Writer
+	stderr  io.Writer
+}
+
+func (b *taskBatch) SetStdin(r io.Reader)  { b.stdin = r }
+func (b *taskBatch) SetStdout(w io.Writer) { b.stdout = w }
+func (b *taskBatch) SetStderr(w io.Writer) { b.stderr = w }
+
+// Run runs the tasks in order, stopping at the first failure unless
+// continueOnError is set, and returns the first error
+func (b *taskBatch) Run() error {
+	var first error
+	for _, task := range b.tasks {
+		cmd := taskCmd.command(task.Taskfile, taskRunArgs(b.opts, task.Name)...)
+		cmd.Stdin, cmd.Stdout, cmd.Stderr = b.stdin, b.stdout, b.stderr
+
+		startRun(task.Name, nil)
+		started := time.Now()
+		err := cmd.Run()
+		finishRun(task.Name, nil, err, time.Since(started))
+		b.results = append(b.results, batchResult{task.Name, err != nil})
+		fmt.Fprintln(b.stderr, resultStatus(task.Name, err, time.Since(started)))
+
+		if err != nil && first == nil {
+			first = err
+		}
+		if err != nil && !continueOnError {
+			break
+		}
+	}
+
+	fmt.Fprintln(b.stderr, batchSummary(b.results, len(b.tasks)))
+	return first
+}
+
+// execTasks runs the marked tasks in the foreground. Like execTask, gt
+// quits when they are done unless keep_open is configured.
+func (m model) execTasks(tasks []Task) (tea.Model, tea.Cmd) {
+	if printSelection {
+		return m.pick(tasks...)
+	}
+	if safeMode {
+		return m.refuseInSafeMode()
+	}
+
+	for _, task := range tasks {
+		selectRun(task.Name)
+	}
+	batch := &taskBatch{tasks: tasks, opts: m.runOptions()}
+	run := tea.Exec(batch, func(error) tea.Msg {
+		return batchFinishedMsg{results: batch.results, tasks: tasks}
+	})
+
+	m.marked = nil
+	if config.KeepOpen {
+		return m, run
+	}
+
+	m.selected = true
+	return m, tea.Sequence(run, tea.Quit)
+}
+
+// execNamespace runs every task in the selected task's namespace, one after
+// the other. A namespace with a default task runs just that, leaving it to
+// the Taskfile to decide what running the whole namespace means.
+func (m model) execNamespace() (tea.Model, tea.Cmd) {
+	selected, ok := m.list.SelectedItem().(Task)
+	if !ok || len(m.filteredList) == 0 {
+		return m, nil
+	}
+	ns := taskNamespace(selected.Name)
+	if ns == "" {
+		m.status = selected.Name + " isn't in a namespace"
+		return m, nil
+	}
+
+	var tasks []Task
+	for _, item := range m.allItems {
+		task := item.(Task)
+		if task.Taskfile != selected.Taskfile || taskNamespace(task.Name) != ns {
+			continue
+		}
+		if task.Name == ns+":default" {
+			return m.execTask(task)
+		}
+		tasks = append(tasks, task)
+	}
+	return m.execTasks(tasks)
+}
+
+// toggleMark adds the selected task to the tasks run together with enter,
+// or removes it if it is already marked
+func (m *model) toggleMark() {
+	task, ok := m.list.SelectedItem().(Task)
+	if !ok || len(m.filteredList) == 0 {
+		return
+	}
+	for i, marked := range m.marked {
+		if marked.Name == task.Name && marked.Taskfile == task.Taskfile {
+			m.marked = append(m.marked[:i:i], m.marked[i+1:]...)
+			return
+		}
+	}
+	m.marked = append(m.marked, task)
+}
+
+// isMarked reports whether the task is marked to run with enter
+func (m model) isMarked(task Task) bool {
+	for _, marked := range m.marked {
+		if marked.Name == task.Name && marked.Taskfile == task.Taskfile {
+			return true
+		}
+	}
+	return false
+}
+
+// batchSummary describes how many tasks of a batch passed, failed and were
+// skipped after a failure
+func batchSummary(results []batchResult, total int) string {
+	var failed []string
+	for _, result := range results {
+		if result.failed {
+			failed = append(failed, result.name)
+		}
+	}
+
+	summary := fmt.Sprintf("%d passed", len(results)-len(failed))
+	if len(failed) > 0 {
+		summary += fmt.Sprintf(", %d failed (%s)", len(failed), strings.Join(failed, ", "))
+	}
+	if skipped := total - len(results); skipped > 0 {
+		summary += fmt.Sprintf(", %d skipped", skipped)
+	}
+	return summary
+}
+
+// splitTaskNames separates the task names in args from the flags and
+// variables that apply to all of them, skipping the values of task's flags
+// as splitTaskArgs does. Everything after '--' is kept for the tasks as
+// CLI_ARGS.
+func splitTaskNames(args []string) ([]string, []string) {
+	var names, shared []string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			shared = append(shared, args[i:]...)
+			break
+		}
+		if taskValueFlags[arg] && i+1 < len(args) {
+			shared = append(shared, arg, args[i+1])
+			i++
+			continue
+		}
+		if strings.HasPrefix(arg, "-") || strings.Contains(arg, "=") {
+			shared = append(shared, arg)
+			continue
+		}
+		names = append(names, arg)
+	}
+	return names, shared
+}
+
+// splitEachTask splits args into the task names and the shared arguments
+// when each task runs in its own task invocation: with --continue-on-error
+// and several tasks, or with --echo
+func splitEachTask(args []string) ([]string, []string, bool) {
+	if !continueOnError && !echoTasks {
+		return nil, nil, false
+	}
+	names, shared := splitTaskNames(args)
+	return names, shared, len(names) > 1 || echoTasks && len(names) == 1
+}
+
+// eachTaskArgs returns the arguments of the task invocation for each name,
+// each with the shared flags and variables
+func eachTaskArgs(names, shared []string) [][]string {
+	// The task name goes before any '--' so it isn't taken as CLI_ARGS
+	flags, cliArgs := shared, []string(nil)
+	if i := slices.Index(shared, "--"); i >= 0 {
+		flags, cliArgs = shared[:i], shared[i:]
+	}
+
+	var each [][]string
+	for _, name := range names {
+		each = append(each, append(append(append([]string{}, flags...), name), cliArgs...))
+	}
+	return each
+}
+
+// runEachTask runs each task in its own task invocation and prints a
+// summary. It carries on past failures with --continue-on-error and stops
+// at the first one otherwise. It returns the exit code of the first task
+// that failed.
+func runEachTask(names, shared []string, stdout, stderr io.Writer) int {
+	code := 0
+	var results []batchResult
+	for i, args := range eachTaskArgs(names, shared) {
+		if echoTasks {
+			fmt.Fprintln(stderr, echoBanner(names[i]))
+		}
+		c := runWithRetries(args, stdout, stderr)
+		if c != 0 && code == 0 {
+			code = c
+		}
+		results = append(results, batchResult{names[i], c != 0})
+		if c != 0 && !continueOnError {
+			break
+		}
+	}
+
+	if len(names) > 1 {
+		fmt.Fprintln(stderr, "gt: "+batchSummary(results, len(names)))
+	}
+	return code
+}
+
+// echoBanner marks the start of a task's output with --echo, styled for
+// stderr, where it is written
+func echoBanner(name string) string {
+	return lipgloss.NewRenderer(os.Stderr).NewStyle().Bold(true).Render("==> running: " + name)
+}
@@ -0,0 +1,28 @@
+package main
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestSplitTaskNames(t *testing.T) {
+	tests := []struct {
+		args   []string
+		names  []string
+		shared []string
+	}{
+		{[]string{"lint", "build"}, []string{"lint", "build"}, nil},
+		{[]string{"-d", "sub", "lint", "build"}, []string{"lint", "build"}, []string{"-d", "sub"}},
+		{[]string{"-o", "prefixed", "build"}, []string{"build"}, []string{"-o", "prefixed"}},
+		{[]string{"-t", "ci.yml", "test", "CI=1"}, []string{"test"}, []string{"-t", "ci.yml", "CI=1"}},
+		{[]string{"-f", "lint", "--dir=sub", "build"}, []string{"lint", "build"}, []string{"-f", "--dir=sub"}},
+		{[]string{"lint", "build", "--", "-d", "x"}, []string{"lint", "build"}, []string{"--", "-d", "x"}},
+		{[]string{"-d"}, nil, []string{"-d"}},
+	}
+	for _, tt := range tests {
+		names, shared := splitTaskNames(tt.args)
+		if !slices.Equal(names, tt.names) || !slices.Equal(shared, tt.shared) {
+			t.Errorf("splitTaskNames(%q) = %q, %q, want %q, %q", tt.args, names, shared, tt.names, tt.shared)
+		}
+	}
+}
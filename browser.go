@@ -0,0 +1,57 @@
+package main
+
+import (
+	"os/exec"
+	"regexp"
+	"runtime"
+	"strings"
+)
+
+// urlPattern finds web links in task descriptions
+var urlPattern = regexp.MustCompile(`https?://[^\s)>\]]+`)
+
+// taskDocURL returns the documentation link of a task: the one configured
+// for it in urls, or else the first link in its description
+func taskDocURL(task Task) string {
+	if url, ok := config.URLs[task.Name]; ok {
+		return url
+	}
+	return strings.TrimRight(urlPattern.FindString(task.Desc), ".,;:")
+}
+
+// openURL opens the URL with the operating system's default handler
+func openURL(url string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("open", url)
+	case "windows":
+		cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", url)
+	default:
+		cmd = exec.Command("xdg-open", url)
+	}
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	// Reap the opener without waiting for it
+	go cmd.Wait()
+	return nil
+}
+
+// openDocs opens the documentation link of the selected task, if it has one
+func (m *model) openDocs() {
+	task, ok := m.list.SelectedItem().(Task)
+	if !ok || len(m.filteredList) == 0 {
+		return
+	}
+
+	url := taskDocURL(task)
+	switch {
+	case url == "":
+		m.status = "no documentation link for " + task.Name
+	case openURL(url) != nil:
+		m.status = "✗ couldn't open " + url
+	default:
+		m.status = "opened " + url
+	}
+}
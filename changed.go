@@ -0,0 +1,32 @@
+package main
+
+import "fmt"
+
+// taskDefinitions maps task names to the fingerprints of their definitions
+type taskDefinitions map[string]string
+
+// taskDefinition fingerprints what a task does, leaving out where it is
+// defined so moving it around isn't a change
+func taskDefinition(task Task) string {
+	task.Line = 0
+	return fmt.Sprintf("%+v", task)
+}
+
+// trackChanges records the task definitions the first time tasks are
+// loaded, and after that marks the tasks that differ from them or are new
+func (m *model) trackChanges(tasks []Task) {
+	if m.baseline == nil {
+		m.baseline = make(taskDefinitions, len(tasks))
+		for _, task := range tasks {
+			m.baseline[task.Name] = taskDefinition(task)
+		}
+		return
+	}
+
+	m.changed = map[string]bool{}
+	for _, task := range tasks {
+		if definition, ok := m.baseline[task.Name]; !ok || definition != taskDefinition(task) {
+			m.changed[task.Name] = true
+		}
+	}
+}
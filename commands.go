@@ -0,0 +1,173 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// dropShadowedCommands removes the commands of root that args don't ask
+// for, so their names reach task. A command only runs when it is the first
+// argument and the Taskfile has no task of that name: a task called serve
+// runs with 'gt serve', and 'gt -d . serve' is passed to task. Arguments
+// after the first, as in 'gt help list', are left alone.
+func dropShadowedCommands(root *cobra.Command, args []string) {
+	if len(args) == 0 {
+		return
+	}
+	for _, sub := range root.Commands() {
+		name := sub.Name()
+		switch {
+		case args[0] == name && definesTask(name):
+		// Cobra skips leading flags to find a command, which would take
+		// 'gt -d . serve' for the serve command
+		case strings.HasPrefix(args[0], "-") && slices.Contains(args, name):
+		default:
+			continue
+		}
+		root.RemoveCommand(sub)
+	}
+}
+
+// definesTask reports whether the Taskfile gt finds has a task called name
+func definesTask(name string) bool {
+	found, _, err := parseTaskfile()
+	return err == nil && taskNamed(found, name)
+}
+
+// runCmd runs a task without any chance of it being mistaken for a gt command
+var runCmd = &cobra.Command{
+	Use:   "run task_name [task flags and args]",
+	Short: "Run a task",
+	Long: `Run a task, passing every argument to task unchanged.
+
+This is the same as 'gt task_name'. Tasks named like gt's own commands,
+such as a task called 'list', take precedence over them, so 'gt list'
+runs the task too.`,
+	Example: `  gt run build         # Run the 'build' task
+  gt run list          # Run a task named 'list'
+  gt run test -- -v    # Pass CLI_ARGS to the 'test' task`,
+	// Everything after 'run' belongs to task
+	DisableFlagParsing: true,
+	Args:               cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if args[0] == "--help" || args[0] == "-h" {
+			cmd.Help()
+			return
+		}
+
+		mustInitialize()
+		os.Exit(runTaskDirect(args))
+	},
+}
+
+// validateCmd checks that the Taskfile can be found and parsed
+var validateCmd = &cobra.Command{
+	Use:   "validate",
+	Short: "Check that the Taskfile can be parsed",
+	Long: `Check that gt can find and parse the Taskfile, and report how many tasks
+it defines. Exits with a non-zero status when the Taskfile is invalid.`,
+	Example: `  gt validate`,
+	Args:    cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		var path string
+		var err error
+		tasks, path, err = parseTaskfile()
+		if err != nil {
+			reportInitError(err)
+			os.Exit(initExitCode(err))
+		}
+
+		if abs, err := filepath.Abs(path); err == nil {
+			path = abs
+		}
+		fmt.Printf("Taskfile: %s\n", path)
+		fmt.Printf("%d tasks, %d with a description\n", len(tasks), len(documentedTasks(tasks)))
+	},
+}
+
+// describeCmd prints everything gt knows about a task
+var describeCmd = &cobra.Command{
+	Use:   "describe task_name",
+	Short: "Show the details of a task",
+	Long: `Show the details gt parsed for a task: its description, environment and
+commands, as shown in the TUI's details view.`,
+	Example: `  gt describe build`,
+	Args:    cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		mustInitialize()
+
+		for _, task := range tasks {
+			if task.Name == args[0] || displayName(task.Name) == args[0] {
+				fmt.Println(task.Name + detailsView(task, -1, true))
+				return
+			}
+		}
+
+		fmt.Fprintf(os.Stderr, "Error: task %q not found\n", args[0])
+		os.Exit(1)
+	},
+}
+
+// initCmd creates a new Taskfile using task itself
+var initCmd = &cobra.Command{
+	Use:     "init",
+	Short:   "Create a new Taskfile in the current directory",
+	Long:    `Create a new Taskfile in the current directory by running 'task --init'.`,
+	Example: `  gt init`,
+	Args:    cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		var err error
+		if taskCmd, err = findTaskCommand(); err != nil {
+			reportInitError(err)
+			os.Exit(initExitCode(err))
+		}
+
+		c := exec.Command(taskCmd.Cmd, append(taskCmd.Args, "--init")...)
+		c.Stdout = os.Stdout
+		c.Stderr = os.Stderr
+		if err := c.Run(); err != nil {
+			os.Exit(exitCode(err))
+		}
+	},
+}
+
+// editCmd opens the Taskfile gt finds in the user's editor
+var editCmd = &cobra.Command{
+	Use:   "edit",
+	Short: "Open the Taskfile in $EDITOR",
+	Long: `Open the Taskfile gt finds, in the current directory or the nearest
+parent directory that has one, in the editor named by $EDITOR.`,
+	Example: `  gt edit
+  EDITOR="code --wait" gt edit`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		path, err := findTaskfile()
+		if err != nil {
+			reportInitError(err)
+			os.Exit(initExitCode(err))
+		}
+
+		editor := strings.Fields(os.Getenv("EDITOR"))
+		if len(editor) == 0 {
+			fmt.Fprintf(os.Stderr, "Error: $EDITOR is not set; set it, e.g. export EDITOR=vi, or open %s yourself\n", path)
+			os.Exit(1)
+		}
+
+		c := exec.Command(editor[0], append(editor[1:], path)...)
+		c.Stdin = os.Stdin
+		c.Stdout = os.Stdout
+		c.Stderr = os.Stderr
+		if err := c.Run(); err != nil {
+			if _, ok := err.(*exec.ExitError); !ok {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			}
+			os.Exit(exitCode(err))
+		}
+	},
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+// testRoot returns a root command with list and serve subcommands, set up
+// the way main does
+func testRoot() *cobra.Command {
+	root := &cobra.Command{Use: "gt", DisableFlagParsing: true, Run: func(*cobra.Command, []string) {}}
+	root.AddCommand(
+		&cobra.Command{Use: "list", Long: "List the tasks.", Run: func(*cobra.Command, []string) {}},
+		&cobra.Command{Use: "serve", Run: func(*cobra.Command, []string) {}},
+	)
+	return root
+}
+
+func TestDropShadowedCommands(t *testing.T) {
+	inProject(t, map[string]string{"Taskfile.yml": "version: '3'\ntasks:\n  serve:\n    cmds: [echo]\n"})
+
+	tests := []struct {
+		args []string
+		kept []string
+	}{
+		{[]string{"list"}, []string{"list", "serve"}},
+		{[]string{"serve"}, []string{"list"}},
+		{[]string{"help", "list"}, []string{"list", "serve"}},
+		{[]string{"build", "list"}, []string{"list", "serve"}},
+		{[]string{"-d", ".", "list"}, []string{"serve"}},
+		{nil, []string{"list", "serve"}},
+	}
+	for _, tt := range tests {
+		root := testRoot()
+		dropShadowedCommands(root, tt.args)
+		var kept []string
+		for _, sub := range root.Commands() {
+			kept = append(kept, sub.Name())
+		}
+		if strings.Join(kept, " ") != strings.Join(tt.kept, " ") {
+			t.Errorf("dropShadowedCommands(%q) kept %q, want %q", tt.args, kept, tt.kept)
+		}
+	}
+}
+
+func TestHelpSubcommand(t *testing.T) {
+	inProject(t, map[string]string{"Taskfile.yml": "version: '3'\ntasks:\n  build:\n    cmds: [echo]\n"})
+
+	root := testRoot()
+	args := []string{"help", "list"}
+	dropShadowedCommands(root, args)
+	var out bytes.Buffer
+	root.SetOut(&out)
+	root.SetArgs(args)
+	if err := root.Execute(); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), "List the tasks.") {
+		t.Errorf("gt help list printed %q, want the list command's help", out.String())
+	}
+}
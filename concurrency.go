@@ -0,0 +1,33 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// concurrency limits how many tasks task runs in parallel, passed with
+// --concurrency; 0 leaves it to task, which doesn't limit them
+var concurrency int
+
+// validateConcurrency checks a limit given with --concurrency
+func validateConcurrency(n int) error {
+	if n < 1 {
+		return fmt.Errorf("--concurrency must be a positive integer, got %d", n)
+	}
+	return nil
+}
+
+// concurrencyName describes the concurrency limit, for the help line
+func concurrencyName(n int) string {
+	if n == 0 {
+		return "unlimited"
+	}
+	return strconv.Itoa(n)
+}
+
+// stepConcurrency raises or lowers the TUI's concurrency limit by delta,
+// lowering it past 1 removing the limit
+func (m *model) stepConcurrency(delta int) {
+	m.concurrency = max(m.concurrency+delta, 0)
+	debugf("concurrency: %s", concurrencyName(m.concurrency))
+}
@@ -0,0 +1,161 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Config holds the user preferences read from gt's config file
+type Config struct {
+	// MaxResults caps how many filter matches are shown, 0 disables the cap
+	MaxResults int `yaml:"max_results"`
+	// CapEmptyFilter applies MaxResults even when the filter is empty
+	CapEmptyFilter bool `yaml:"cap_empty_filter"`
+	// Menu lists quick-launch tasks shown first and run with the keys 1-9
+	Menu []string `yaml:"menu"`
+	// HyperlinkFormat is the link target for task names in terminals that
+	// support OSC 8 hyperlinks; {path} and {line} are replaced with the
+	// task's location, e.g. "vscode://file{path}:{line}"
+	HyperlinkFormat string `yaml:"hyperlink_format"`
+	// KeepOpen returns to the list after running a task instead of quitting
+	KeepOpen bool `yaml:"keep_open"`
+	// LineNumbers shows a number before each task; typing a number in
+	// navigation mode and pressing enter moves the selection to it
+	LineNumbers bool `yaml:"line_numbers"`
+	// Env sets extra environment variables for every task run, on top of
+	// gt's own environment; --env overrides them
+	Env map[string]string `yaml:"env"`
+	// Exclude hides tasks whose names match these path.Match patterns
+	Exclude []string `yaml:"exclude"`
+	// URLs maps task names to documentation links opened with u, taking
+	// precedence over links found in task descriptions
+	URLs map[string]string `yaml:"urls"`
+	// Header shows the project name, Taskfile version and task count above
+	// the filter
+	Header bool `yaml:"header"`
+	// Theme is the color preset of the TUI: dark, light or high-contrast
+	Theme string `yaml:"theme"`
+	// FilterUnknown opens the TUI filtered with the name of a task that
+	// doesn't exist, instead of running task with it; see --filter-unknown
+	FilterUnknown bool `yaml:"filter_unknown"`
+	// TagPattern is the regexp finding tags in task descriptions, whose
+	// first group is the tag name; tags are written [ci] by default
+	TagPattern string `yaml:"tag_pattern"`
+	// ConfirmQuit asks before quitting with q or esc while tasks are
+	// marked, so the selection isn't lost by accident
+	ConfirmQuit bool `yaml:"confirm_quit"`
+	// AutoRun runs the task the filter narrows down to once typing pauses;
+	// see --auto-run
+	AutoRun bool `yaml:"auto_run"`
+	// AutoRunExclude are patterns of tasks never auto-run, e.g. "deploy:*".
+	// It defaults to defaultAutoRunExclude; set it to [] to auto-run
+	// every task.
+	AutoRunExclude []string `yaml:"auto_run_exclude"`
+	// HideModes hides the summary of the active filter mode and view
+	// toggles at the start of the help line
+	HideModes bool `yaml:"hide_modes"`
+	// Scratch lets ! run ad-hoc shell commands in the Taskfile's directory
+	Scratch bool `yaml:"scratch"`
+	// StopAtRepoRoot stops the search for a Taskfile in the parent
+	// directories at the root of the git repository, if there is one
+	StopAtRepoRoot bool `yaml:"stop_at_repo_root"`
+	// DescSeparator shows the descriptions of shift+tab after the task
+	// names, separated by it, e.g. " - ", instead of on their own lines
+	DescSeparator string `yaml:"desc_separator"`
+	// DescField is the task field listed as its description, desc or
+	// summary; the other one is used when it is empty
+	DescField string `yaml:"desc_field"`
+	// Density is the room each task takes in the list: compact, normal
+	// or comfortable; = cycles it in the TUI
+	Density string `yaml:"density"`
+	// Keymap is the preset of navigation keys added to the defaults:
+	// default, vim or emacs; see --keymap
+	Keymap string `yaml:"keymap"`
+	// Colors picks the color of tasks whose names match a pattern; the
+	// first matching rule wins
+	Colors []ColorRule `yaml:"colors"`
+}
+
+// ColorRule colors the tasks whose names match Pattern, which uses
+// path.Match syntax, e.g. "deploy:*". Color is a basic color name such as
+// red, an ANSI color number or a hex color.
+type ColorRule struct {
+	Pattern string `yaml:"pattern"`
+	Color   string `yaml:"color"`
+}
+
+// config is the active configuration, loaded at startup
+var config = defaultConfig()
+
+// defaultConfig returns the configuration used when no config file exists
+func defaultConfig() Config {
+	return Config{
+		MaxResults:      200,
+		HyperlinkFormat: "file://{path}",
+		ConfirmQuit:     true,
+		StopAtRepoRoot:  true,
+		AutoRunExclude:  defaultAutoRunExclude,
+	}
+}
+
+// configPath returns the location of the config file
+func configPath() (string, error) {
+	dir, err := configDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "config.yml"), nil
+}
+
+// resetConfig returns the defaults, undoing the settings a config file
+// that failed to load may have applied already
+func resetConfig() Config {
+	tagPattern = regexp.MustCompile(defaultTagPattern)
+	setKeymap("")
+	return defaultConfig()
+}
+
+// loadConfig reads the config file on top of the defaults. A missing config
+// file is not an error.
+func loadConfig() (Config, error) {
+	cfg := defaultConfig()
+
+	path, err := configPath()
+	if err != nil {
+		return cfg, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return cfg, nil
+	}
+	if err != nil {
+		return cfg, err
+	}
+
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return cfg, err
+	}
+	if err := checkColorRules(cfg.Colors); err != nil {
+		return cfg, err
+	}
+	if err := setTagPattern(cfg.TagPattern); err != nil {
+		return cfg, err
+	}
+	if err := setKeymap(cfg.Keymap); err != nil {
+		return cfg, err
+	}
+	if cfg.DescField != "" && cfg.DescField != "desc" && cfg.DescField != "summary" {
+		return cfg, fmt.Errorf("desc_field %q: expected desc or summary", cfg.DescField)
+	}
+	if _, err := parseDensity(cfg.Density); err != nil {
+		return cfg, err
+	}
+
+	return cfg, nil
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestResetConfigAfterBrokenConfig(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", dir)
+	t.Setenv("HOME", dir)
+	path, err := configPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	os.MkdirAll(filepath.Dir(path), 0o755)
+	broken := "tag_pattern: '#(\\w+)'\nkeymap: nano\n"
+	if err := os.WriteFile(path, []byte(broken), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := loadConfig(); err == nil {
+		t.Fatal("loadConfig() accepted an unknown keymap")
+	}
+	cfg := resetConfig()
+	if cfg.Keymap != "" || activeKeymap.name != keymaps[0].name {
+		t.Errorf("keymap = %q/%q, want the default", cfg.Keymap, activeKeymap.name)
+	}
+	if tagPattern.String() != defaultTagPattern {
+		t.Errorf("tag pattern = %q, want the default", tagPattern)
+	}
+}
+
+func TestAutoRunExclude(t *testing.T) {
+	tests := []struct {
+		name    string
+		exclude []string
+		runs    bool
+	}{
+		{"build", defaultAutoRunExclude, true},
+		{"deploy", defaultAutoRunExclude, false},
+		{"k8s:deploy-prod", defaultAutoRunExclude, false},
+		{"db:reset", defaultAutoRunExclude, false},
+		{"clean", defaultAutoRunExclude, false},
+		{"clean", nil, true},
+	}
+	for _, tt := range tests {
+		m := loadedModel(t, []Task{{Name: tt.name}}, 10)
+		m.filter.SetValue(tt.name)
+		m.applyFilter()
+
+		saved := config
+		config.AutoRun, config.AutoRunExclude = true, tt.exclude
+		_, runs := m.autoRunTask()
+		config = saved
+		if runs != tt.runs {
+			t.Errorf("auto-running %s with exclude %q = %v, want %v", tt.name, tt.exclude, runs, tt.runs)
+		}
+	}
+}
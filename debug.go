@@ -0,0 +1,28 @@
+package main
+
+import (
+	"io"
+	"log"
+)
+
+// debug turns on gt's internal logging, set with --debug
+var debug bool
+
+// debugLog writes gt's internal logs. It discards them unless --debug is
+// given.
+var debugLog = log.New(io.Discard, "gt: ", log.Ltime|log.Lmicroseconds)
+
+// startDebugLog sends the internal logs to w when --debug or --profile is
+// given
+func startDebugLog(w io.Writer) {
+	if debug || profile {
+		debugLog.SetOutput(w)
+	}
+}
+
+// debugf logs an internal event when --debug is given
+func debugf(format string, args ...any) {
+	if debug {
+		debugLog.Printf(format, args...)
+	}
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// density controls how much room the task list takes per task
+type density int
+
+const (
+	densityCompact density = iota
+	densityNormal
+	densityComfortable
+)
+
+// densityNames are the names of the densities, as set in the config file
+var densityNames = []string{"compact", "normal", "comfortable"}
+
+func (d density) String() string {
+	return densityNames[d]
+}
+
+// next returns the density that follows d in the cycle
+func (d density) next() density {
+	return (d + 1) % density(len(densityNames))
+}
+
+// spacing is the number of blank lines between tasks
+func (d density) spacing() int {
+	if d == densityComfortable {
+		return 1
+	}
+	return 0
+}
+
+// indent is the padding left of the task names
+func (d density) indent() int {
+	if d == densityCompact {
+		return 0
+	}
+	return 2
+}
+
+// parseDensity returns the density with the given name, compact when it is
+// empty
+func parseDensity(name string) (density, error) {
+	if name == "" {
+		return densityCompact, nil
+	}
+	for i, n := range densityNames {
+		if n == name {
+			return density(i), nil
+		}
+	}
+	return densityCompact, fmt.Errorf("density %q: expected %s", name, strings.Join(densityNames, ", "))
+}
+
+// cycleDensity switches to the next density, resizing the list's pages to
+// the room each task now takes
+func (m *model) cycleDensity() {
+	m.density = m.density.next()
+	m.list.SetDelegate(newDelegate(m.currentTheme(), m.allDescs, m.density))
+	m.status = "density: " + m.density.String()
+}
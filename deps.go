@@ -0,0 +1,93 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// depIndex returns the index of the dependency chosen in the details view,
+// or -1 when the dependency list isn't focused
+func (m model) depIndex() int {
+	if !m.depFocus {
+		return -1
+	}
+	return m.dep
+}
+
+// updateDeps handles moving through the dependencies of the selected task
+// in the details view, and running one with enter. It reports whether the
+// key was handled.
+func (m model) updateDeps(key string) (model, tea.Cmd, bool) {
+	task, ok := m.list.SelectedItem().(Task)
+	if !ok || !m.expanded || len(task.Deps) == 0 || m.filter.Focused() {
+		m.depFocus = false
+		return m, nil, false
+	}
+
+	if !m.depFocus {
+		if key == "right" || key == "l" {
+			m.depFocus, m.dep = true, 0
+			return m, nil, true
+		}
+		return m, nil, false
+	}
+
+	switch key {
+	case "up", "k":
+		m.dep = max(m.dep-1, 0)
+	case "down", "j":
+		m.dep = min(m.dep+1, len(task.Deps)-1)
+	case "left", "h", "esc":
+		m.depFocus = false
+	case "enter":
+		name := task.Deps[m.dep]
+		m.depFocus = false
+		if dep, ok := m.resolveDep(task, name); ok {
+			next, cmd := m.execTask(dep)
+			return next.(model), cmd, true
+		}
+		m.status = "dependency " + name + " not found"
+	default:
+		// Anything else leaves the dependency list and is handled as usual
+		m.depFocus = false
+		return m, nil, false
+	}
+	return m, nil, true
+}
+
+// resolveDep finds the task a dependency name refers to. Names are relative
+// to the namespace of the task that depends on them, and a leading colon
+// refers to the root Taskfile.
+func (m model) resolveDep(parent Task, name string) (Task, bool) {
+	var candidates []string
+	if root, ok := strings.CutPrefix(name, ":"); ok {
+		candidates = []string{root}
+	} else {
+		if ns := taskNamespace(parent.Name); ns != "" {
+			candidates = append(candidates, ns+":"+name)
+		}
+		candidates = append(candidates, name)
+	}
+
+	for _, candidate := range candidates {
+		for _, item := range m.allItems {
+			if task := item.(Task); task.Name == candidate {
+				return task, true
+			}
+		}
+	}
+	return Task{}, false
+}
+
+// depChips renders the direct dependencies of a task on one line, like
+// "deps: build · lint", fitting width by leaving out the last ones
+func depChips(deps []string, width int) string {
+	line := "deps: " + strings.Join(deps, " · ")
+	for shown := len(deps) - 1; lipgloss.Width(line) > width && shown > 0; shown-- {
+		line = fmt.Sprintf("deps: %s … +%d", strings.Join(deps[:shown], " · "), len(deps)-shown)
+	}
+	return line
+}
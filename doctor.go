@@ -0,0 +1,116 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+
+	"github.com/spf13/cobra"
+)
+
+// doctorCmd compares the tasks gt parsed with the tasks task lists
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Compare the tasks gt parsed with the tasks task lists",
+	Long: `Compare the tasks gt found by parsing the Taskfile with the tasks
+'task --list-all --json' reports, and list the tasks only one of them knows
+about. This shows what gt's parser misses, which helps when filing bugs.
+
+Exclude patterns are ignored, so every task is compared. Exits with a
+non-zero status when the lists differ. Needs task ` + minJSONListVersion.String() + ` or newer.`,
+	Example: `  gt doctor`,
+	Args:    cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		showAll = true
+		mustInitialize()
+
+		if !taskSupports(minJSONListVersion) {
+			fmt.Fprintf(os.Stderr, "Error: task %s or newer is needed to compare the task lists\n", minJSONListVersion)
+			os.Exit(1)
+		}
+		listed, err := listedTasks()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: task --list-all --json: %v\n", err)
+			os.Exit(1)
+		}
+
+		var parsed, names []string
+		for _, task := range tasks {
+			parsed = append(parsed, task.Name)
+		}
+		for _, task := range listed {
+			names = append(names, task.Name)
+		}
+		if !printTaskDiff(os.Stdout, parsed, names) {
+			os.Exit(1)
+		}
+	},
+}
+
+// listedTask is a task as 'task --list-all --json' reports it
+type listedTask struct {
+	Name string `json:"name"`
+	Desc string `json:"desc"`
+	*TaskInfo
+}
+
+// listedTasks returns the tasks 'task --list-all --json' reports
+func listedTasks() ([]listedTask, error) {
+	return listedTasksIn(passthroughTaskfile(nil))
+}
+
+// listedTasksIn returns the tasks 'task --list-all --json' reports for the
+// given Taskfile, or the one task finds when it is empty
+func listedTasksIn(taskfile string) ([]listedTask, error) {
+	out, err := taskCmd.command(taskfile, "--list-all", "--json").Output()
+	if err != nil {
+		return nil, err
+	}
+
+	var list struct {
+		Tasks []listedTask `json:"tasks"`
+	}
+	if err := json.Unmarshal(out, &list); err != nil {
+		return nil, err
+	}
+	return list.Tasks, nil
+}
+
+// printTaskDiff writes the task names only one of the lists has, and
+// reports whether the lists agree
+func printTaskDiff(w io.Writer, parsed, listed []string) bool {
+	var onlyTask, onlyGt []string
+	for _, name := range listed {
+		if !slices.Contains(parsed, name) {
+			onlyTask = append(onlyTask, name)
+		}
+	}
+	for _, name := range parsed {
+		if !slices.Contains(listed, name) {
+			onlyGt = append(onlyGt, name)
+		}
+	}
+	slices.Sort(onlyTask)
+	slices.Sort(onlyGt)
+
+	fmt.Fprintf(w, "gt parsed %d tasks, task lists %d\n", len(parsed), len(listed))
+	if len(onlyTask) == 0 && len(onlyGt) == 0 {
+		fmt.Fprintln(w, "The lists agree")
+		return true
+	}
+	if len(onlyTask) > 0 {
+		fmt.Fprintln(w, "\nOnly listed by task, missed by gt's parser:")
+		for _, name := range onlyTask {
+			fmt.Fprintln(w, "  - "+name)
+		}
+	}
+	if len(onlyGt) > 0 {
+		fmt.Fprintln(w, "\nOnly parsed by gt, not listed by task:")
+		for _, name := range onlyGt {
+			fmt.Fprintln(w, "  - "+name)
+		}
+	}
+	return false
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"time"
+)
+
+// emitEvents writes run lifecycle events as JSON lines to stderr
+var emitEvents bool
+
+// eventLog is where events are written. The TUI holds them back until it
+// is done, as it draws over the terminal.
+var eventLog io.Writer = os.Stderr
+
+// runEvent is one line of the --events stream. The schema is stable:
+// fields are only ever added.
+//
+//	event        "task-selected", "task-started" or "task-finished"
+//	time         when the event happened, RFC 3339
+//	task         name of the task
+//	args         other arguments passed to task, if any
+//	exit_code    exit code of the run, task-finished only
+//	duration_ms  how long the run took, task-finished only
+type runEvent struct {
+	Event      string    `json:"event"`
+	Time       time.Time `json:"time"`
+	Task       string    `json:"task"`
+	Args       []string  `json:"args,omitempty"`
+	ExitCode   *int      `json:"exit_code,omitempty"`
+	DurationMs *int64    `json:"duration_ms,omitempty"`
+}
+
+// emitEvent writes the event to eventLog when --events is set
+func emitEvent(event runEvent) {
+	if !emitEvents {
+		return
+	}
+	event.Time = time.Now()
+	json.NewEncoder(eventLog).Encode(event)
+}
+
+// selectRun reports that a task was chosen in the TUI
+func selectRun(task string) {
+	emitEvent(runEvent{Event: "task-selected", Task: task})
+}
+
+// startRun reports that a task is about to run
+func startRun(task string, args []string) {
+	emitEvent(runEvent{Event: "task-started", Task: task, Args: args})
+}
+
+// finishRun records a finished run in the history log and reports it
+func finishRun(task string, args []string, err error, elapsed time.Duration) {
+	recordHistory(task, args, err)
+
+	code, ms := exitCode(err), elapsed.Milliseconds()
+	emitEvent(runEvent{Event: "task-finished", Task: task, Args: args, ExitCode: &code, DurationMs: &ms})
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Exit statuses of gt's own failures, described in exitCodes. The statuses
+// of tasks are passed through as they are, so a task exiting with one of
+// these can't be told apart from gt's failure.
+const (
+	exitError       = 1
+	exitNoTaskfile  = 3
+	exitNoTask      = 4
+	exitNoTasks     = 5
+	exitNoSelection = 130
+)
+
+// exitCodes describes each of gt's exit statuses, for the root command's
+// help
+var exitCodes = []struct {
+	code    int
+	meaning string
+}{
+	{exitError, "any other error, e.g. an invalid Taskfile or a bad flag"},
+	{exitNoTaskfile, "no Taskfile was found"},
+	{exitNoTask, "task is not installed"},
+	{exitNoTasks, "the Taskfile defines no tasks"},
+	{exitNoSelection, "--print-selection quit without choosing a task"},
+}
+
+// exitCodeTable renders exitCodes as an indented table, followed by a
+// warning that a task's own status can be the same
+func exitCodeTable() string {
+	var b strings.Builder
+	for _, e := range exitCodes {
+		fmt.Fprintf(&b, "  %-4d %s\n", e.code, e.meaning)
+	}
+	b.WriteString("\nA task that exits with one of these statuses itself looks the same,\nsince task's status is passed through unchanged.\n")
+	return b.String()
+}
+
+// initExitCode returns the exit status for an error finding task or
+// loading the tasks
+func initExitCode(err error) int {
+	switch {
+	case errors.Is(err, ErrNoTaskfile):
+		return exitNoTaskfile
+	case errors.Is(err, ErrTaskNotFound):
+		return exitNoTask
+	case errors.Is(err, ErrNoTasks):
+		return exitNoTasks
+	default:
+		return exitError
+	}
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// exportFormat is the output format of 'gt export': md or csv
+var exportFormat string
+
+// exportAll includes tasks without a description in the export
+var exportAll bool
+
+// exportCmds adds each task's commands to the export
+var exportCmds bool
+
+// exportCmd prints the tasks as a Markdown table or CSV
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Print the tasks as a Markdown table or CSV",
+	Long: `Print the tasks as a Markdown table, ready to paste into a README, or
+as CSV.
+
+Like 'gt list', only tasks with a description are exported by default.
+Use --all to include every task and --cmds to add their commands.`,
+	Example: `  gt export                   # Markdown table of documented tasks
+  gt export --all --cmds      # Every task, with its commands
+  gt export --format csv      # CSV with a header row`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if exportFormat != "md" && exportFormat != "csv" {
+			return fmt.Errorf("unknown format %q, expected md or csv", exportFormat)
+		}
+
+		mustInitialize()
+		exported := tasks
+		if !exportAll {
+			exported = documentedTasks(tasks)
+		}
+
+		if exportFormat == "csv" {
+			return writeCSV(os.Stdout, exported, exportCmds)
+		}
+		writeMarkdown(os.Stdout, exported, exportCmds)
+		return nil
+	},
+}
+
+// writeMarkdown writes the tasks as a Markdown table
+func writeMarkdown(w io.Writer, tasks []Task, cmds bool) {
+	if cmds {
+		fmt.Fprintln(w, "| Task | Description | Commands |")
+		fmt.Fprintln(w, "| --- | --- | --- |")
+	} else {
+		fmt.Fprintln(w, "| Task | Description |")
+		fmt.Fprintln(w, "| --- | --- |")
+	}
+
+	for _, task := range tasks {
+		row := "| `" + task.Name + "` | " + markdownCell(task.Desc) + " |"
+		if cmds {
+			var cells []string
+			for _, cmd := range task.Cmds {
+				cells = append(cells, markdownCell(cmd))
+			}
+			row += " " + strings.Join(cells, "<br>") + " |"
+		}
+		fmt.Fprintln(w, row)
+	}
+}
+
+// markdownCell escapes text for a Markdown table cell, where pipes would
+// end the cell and newlines the row
+func markdownCell(text string) string {
+	text = strings.ReplaceAll(text, "|", `\|`)
+	return strings.ReplaceAll(strings.TrimSpace(text), "\n", "<br>")
+}
+
+// writeCSV writes the tasks as CSV with a header row. Commands are joined
+// with newlines in a single field.
+func writeCSV(w io.Writer, tasks []Task, cmds bool) error {
+	cw := csv.NewWriter(w)
+	header := []string{"name", "description"}
+	if cmds {
+		header = append(header, "commands")
+	}
+	cw.Write(header)
+
+	for _, task := range tasks {
+		record := []string{task.Name, task.Desc}
+		if cmds {
+			record = append(record, strings.Join(task.Cmds, "\n"))
+		}
+		cw.Write(record)
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
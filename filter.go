@@ -0,0 +1,263 @@
+package main
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/sahilm/fuzzy"
+)
+
+// fuzzyFilter filters the list items based on the input, keeping at most
+// limit of the best matches (0 means no limit). It also returns the total
+// number of matches before the limit was applied.
+//
+// A query starting with a namespace followed by a colon, like "docker:bu",
+// only matches tasks in that namespace, fuzzy-matching the rest of the query
+// against the task names within it.
+//
+// A query starting with ' matches task names containing the rest of the
+// query, ignoring case, and one starting with / matches them against the
+// rest as a regular expression. Both keep the tasks in list order. An
+// invalid regular expression is returned as an error.
+//
+// Words like #ci restrict the matches to the tasks with a tag starting
+// with the word.
+//
+// Fuzzy and exact matching ignore case unless matchCase is set.
+func fuzzyFilter(items []list.Item, filter string, limit int, matchCase bool) ([]list.Item, int, error) {
+	// Restrict to the tasks with the #tags in the filter first
+	items, filter = tagScope(items, filter)
+	if filter == "" {
+		filtered, total := capItems(items, limit)
+		return filtered, total, nil
+	}
+
+	// Extract the string values to match against
+	var targets []string
+	if scoped, leaves, rest, ok := namespaceScope(items, filter); ok {
+		if rest == "" {
+			filtered, total := capItems(scoped, limit)
+			return filtered, total, nil
+		}
+		items, targets, filter = scoped, leaves, rest
+	} else {
+		for _, item := range items {
+			targets = append(targets, item.FilterValue())
+		}
+	}
+
+	indexes, err := matchTargets(filter, targets, matchCase)
+	if err != nil {
+		return nil, 0, err
+	}
+	total := len(indexes)
+	if limit > 0 && total > limit {
+		indexes = indexes[:limit]
+	}
+
+	// Create a new slice with the matching items in order
+	var filtered []list.Item
+	for _, i := range indexes {
+		filtered = append(filtered, items[i])
+	}
+
+	return filtered, total, nil
+}
+
+// matchTargets returns the indexes of the targets matching the query, in
+// the order they should be listed
+func matchTargets(query string, targets []string, matchCase bool) ([]int, error) {
+	var indexes []int
+	switch {
+	case strings.HasPrefix(query, "'"):
+		// Exact substring, like fzf
+		substr := query[1:]
+		for i, target := range targets {
+			if !matchCase {
+				substr, target = strings.ToLower(substr), strings.ToLower(target)
+			}
+			if strings.Contains(target, substr) {
+				indexes = append(indexes, i)
+			}
+		}
+	case strings.HasPrefix(query, "/"):
+		re, err := regexp.Compile(query[1:])
+		if err != nil {
+			return nil, fmt.Errorf("invalid regexp: %w", err)
+		}
+		for i, target := range targets {
+			if re.MatchString(target) {
+				indexes = append(indexes, i)
+			}
+		}
+	default:
+		// Perform fuzzy matching, best matches first. The fuzzy library
+		// always ignores case, so drop the matches that differ in case.
+		var matches fuzzy.Matches
+		for _, match := range fuzzy.Find(query, targets) {
+			if !matchCase || isSubsequence(query, match.Str) {
+				match.Score += matchBonus(query, match.Str)
+				matches = append(matches, match)
+			}
+		}
+		sort.SliceStable(matches, func(i, j int) bool {
+			if matches[i].Score != matches[j].Score {
+				return matches[i].Score > matches[j].Score
+			}
+			return matches[i].Str < matches[j].Str
+		})
+		for _, match := range matches {
+			indexes = append(indexes, match.Index)
+		}
+	}
+	return indexes, nil
+}
+
+// Bonuses added to the fuzzy score, so short queries land on the task
+// they obviously mean
+const (
+	acronymBonus    = 100 // gb for git:build
+	wordPrefixBonus = 50  // bui for git:build
+)
+
+// matchBonus scores how well query matches the words of target, which are
+// separated by -, : or _. Matching the first letters of the words beats
+// matching the start of a word.
+func matchBonus(query, target string) int {
+	query = strings.ToLower(query)
+	words := strings.FieldsFunc(strings.ToLower(target), func(r rune) bool {
+		return r == '-' || r == ':' || r == '_'
+	})
+
+	var acronym strings.Builder
+	for _, word := range words {
+		r, _ := utf8.DecodeRuneInString(word)
+		acronym.WriteRune(r)
+	}
+	if len(words) > 1 && strings.HasPrefix(acronym.String(), query) {
+		return acronymBonus
+	}
+	for _, word := range words {
+		if strings.HasPrefix(word, query) {
+			return wordPrefixBonus
+		}
+	}
+	return 0
+}
+
+// isSubsequence reports whether the runes of query appear in s in order,
+// comparing case
+func isSubsequence(query, s string) bool {
+	rest := []rune(query)
+	for _, r := range s {
+		if len(rest) > 0 && r == rest[0] {
+			rest = rest[1:]
+		}
+	}
+	return len(rest) == 0
+}
+
+// editDistance returns the number of single rune insertions, deletions
+// and substitutions turning a into b, ignoring case
+func editDistance(a, b string) int {
+	ra, rb := []rune(strings.ToLower(a)), []rune(strings.ToLower(b))
+	prev := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := range ra {
+		cur := []int{i + 1}
+		for j := range rb {
+			cost := 1
+			if ra[i] == rb[j] {
+				cost = 0
+			}
+			cur = append(cur, min(prev[j]+cost, prev[j+1]+1, cur[j]+1))
+		}
+		prev = cur
+	}
+	return prev[len(rb)]
+}
+
+// capItems applies the result limit to an unfiltered list when configured
+func capItems(items []list.Item, limit int) ([]list.Item, int) {
+	if limit > 0 && config.CapEmptyFilter && len(items) > limit {
+		return items[:limit], len(items)
+	}
+	return items, len(items)
+}
+
+// namespaceScope checks whether filter starts with the namespace of some of
+// the tasks, like "docker:" in "docker:bu". If so, it returns the tasks in
+// that namespace, their names with the namespace removed, and the rest of
+// the query.
+func namespaceScope(items []list.Item, filter string) ([]list.Item, []string, string, bool) {
+	i := strings.LastIndex(filter, ":")
+	if i <= 0 {
+		return nil, nil, "", false
+	}
+	prefix, rest := filter[:i+1], filter[i+1:]
+
+	var scoped []list.Item
+	var leaves []string
+	for _, item := range items {
+		name := item.(Task).Name
+		if strings.HasPrefix(name, prefix) {
+			scoped = append(scoped, item)
+			leaves = append(leaves, name[len(prefix):])
+		}
+	}
+	if len(scoped) == 0 {
+		return nil, nil, "", false
+	}
+
+	return scoped, leaves, rest, true
+}
+
+// displayName returns the name a task is listed under. The default task
+// of an included Taskfile is shown as its namespace with a trailing colon,
+// like "docker:" for "docker:default", but still runs as docker:default.
+func displayName(name string) string {
+	if ns, ok := strings.CutSuffix(name, ":default"); ok && ns != "" {
+		return ns + ":"
+	}
+	return name
+}
+
+// taskNamespace returns the namespace part of a task name, like "docker"
+// for "docker:build", or "" for tasks outside any namespace
+func taskNamespace(name string) string {
+	if i := strings.LastIndex(name, ":"); i >= 0 {
+		return name[:i]
+	}
+	return ""
+}
+
+// excludeTasks drops the tasks whose names match any of the patterns, which
+// use path.Match syntax, e.g. "ci:*"
+func excludeTasks(tasks []Task, patterns []string) ([]Task, error) {
+	if len(patterns) == 0 {
+		return tasks, nil
+	}
+
+	var kept []Task
+	for _, task := range tasks {
+		excluded := false
+		for _, pattern := range patterns {
+			match, err := path.Match(pattern, task.Name)
+			if err != nil {
+				return nil, fmt.Errorf("exclude pattern %q: %w", pattern, err)
+			}
+			excluded = excluded || match
+		}
+		if !excluded {
+			kept = append(kept, task)
+		}
+	}
+	return kept, nil
+}
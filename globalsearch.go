@@ -0,0 +1,149 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// globalSearch lists the tasks of every Taskfile in the repository, set
+// with --global-search
+var globalSearch bool
+
+// globalCacheTTL is how long the Taskfiles found in a repository are
+// reused before looking for them again
+const globalCacheTTL = time.Hour
+
+// cachedTaskfiles are the Taskfiles found in a repository
+type cachedTaskfiles struct {
+	Found time.Time `json:"found"`
+	Paths []string  `json:"paths"`
+}
+
+// repoRoot returns the root of the git repository of the working
+// directory, or else the directory searched for the Taskfile picker
+func repoRoot() string {
+	out, err := exec.Command("git", "rev-parse", "--show-toplevel").Output()
+	if err != nil {
+		return scanRoot()
+	}
+	return filepath.Clean(strings.TrimSpace(string(out)))
+}
+
+// globalTaskfiles returns the Taskfiles of the repository, reusing the
+// ones found by an earlier run when they are recent and all still exist
+func globalTaskfiles() []string {
+	defer profiled("discovery", time.Now())
+	root := repoRoot()
+	cache := loadTaskfileCache()
+	if cached, ok := cache[root]; ok && time.Since(cached.Found) < globalCacheTTL && allExist(cached.Paths) {
+		debugf("global search: %d cached Taskfiles in %s", len(cached.Paths), root)
+		return cached.Paths
+	}
+
+	paths := discoverTaskfiles(root)
+	debugf("global search: found %d Taskfiles in %s", len(paths), root)
+	cache[root] = cachedTaskfiles{Found: time.Now(), Paths: paths}
+	saveTaskfileCache(cache)
+	return paths
+}
+
+// discoverTaskfiles returns the Taskfile of each directory of the
+// repository at root. git lists them so ignored directories are skipped;
+// outside a repository the directories are walked instead.
+func discoverTaskfiles(root string) []string {
+	args := []string{"-C", root, "ls-files", "--cached", "--others", "--exclude-standard", "--"}
+	for _, name := range searchedTaskfileNames {
+		args = append(args, ":(glob)**/"+name)
+	}
+	out, err := exec.Command("git", args...).Output()
+	if err != nil {
+		return findTaskfiles(root)
+	}
+
+	// Take the Taskfile task itself would use in each directory
+	dirs := map[string]bool{}
+	var paths []string
+	for _, line := range strings.Split(string(out), "\n") {
+		if line == "" {
+			continue
+		}
+		dir := filepath.Dir(filepath.Join(root, filepath.FromSlash(line)))
+		if dirs[dir] {
+			continue
+		}
+		dirs[dir] = true
+		if path, ok := hasTaskfile(dir); ok {
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// allExist reports whether all the files exist
+func allExist(paths []string) bool {
+	for _, path := range paths {
+		if _, err := os.Stat(path); err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// taskfileCachePath returns the location of the cache of found Taskfiles
+func taskfileCachePath() (string, error) {
+	dir, err := configDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "taskfiles.json"), nil
+}
+
+// loadTaskfileCache reads the Taskfiles found by earlier runs, by
+// repository root. A missing or unreadable cache is empty.
+func loadTaskfileCache() map[string]cachedTaskfiles {
+	cache := map[string]cachedTaskfiles{}
+	if path, err := taskfileCachePath(); err == nil {
+		if data, err := os.ReadFile(path); err == nil {
+			json.Unmarshal(data, &cache)
+		}
+	}
+	return cache
+}
+
+// saveTaskfileCache writes the cache of found Taskfiles. Like history, the
+// cache is best effort, so failures to write it are ignored.
+func saveTaskfileCache(cache map[string]cachedTaskfiles) {
+	path, err := taskfileCachePath()
+	if err != nil {
+		return
+	}
+	data, err := json.Marshal(cache)
+	if err != nil {
+		return
+	}
+	if os.MkdirAll(filepath.Dir(path), 0o755) == nil {
+		os.WriteFile(path, data, 0o644)
+	}
+}
+
+// workingDir is the directory gt was started in
+var workingDir = sync.OnceValue(func() string {
+	dir, _ := os.Getwd()
+	return dir
+})
+
+// taskfileTag returns how a Taskfile is shown next to its tasks: its path
+// relative to the working directory, when that is shorter
+func taskfileTag(path string) string {
+	if rel, err := filepath.Rel(workingDir(), path); err == nil && len(rel) < len(path) {
+		return rel
+	}
+	return path
+}
@@ -0,0 +1,95 @@
+package main
+
+import "strings"
+
+// gridGap is the space left between columns of the grid layout
+const gridGap = 2
+
+// gridColumns returns how many columns the task list is laid out in. The
+// grid only applies to a flat list with details and descriptions hidden;
+// otherwise, or when the terminal is too narrow, it is a single column.
+func (m model) gridColumns() int {
+	if !m.grid || m.expanded || m.allDescs || m.grouping != groupFlat || len(m.filteredList) == 0 {
+		return 1
+	}
+
+	widest := 0
+	for i, item := range m.filteredList {
+		_, width := m.taskLabel(item.(Task), i)
+		widest = max(widest, width)
+	}
+	return max((m.width-m.density.indent())/(widest+gridGap), 1)
+}
+
+// gridRows returns how many rows of the grid fit on screen, with the blank
+// lines the density leaves between them
+func (m model) gridRows() int {
+	spacing := m.density.spacing()
+	return max((m.height-m.chromeHeight()+spacing)/(1+spacing), 1)
+}
+
+// viewGrid renders the visible rows of the task grid, flowing names across
+// the columns like ls
+func (m model) viewGrid(cols int) string {
+	indent := strings.Repeat(" ", m.density.indent())
+	cellWidth := (m.width - len(indent)) / cols
+	selected := m.list.Index()
+
+	// Page through the rows so the selected task is always visible
+	rows := m.gridRows()
+	first := (selected / cols / rows) * rows * cols
+	last := min(first+rows*cols, len(m.filteredList))
+
+	var b strings.Builder
+	for i := first; i < last; i++ {
+		task := m.filteredList[i].(Task)
+		label, width := m.taskLabel(task, i)
+		if (i-first)%cols == 0 {
+			if i > first {
+				b.WriteString(strings.Repeat("\n", m.density.spacing()))
+			}
+			b.WriteString(indent)
+		}
+		b.WriteString(m.taskStyle(task, i == selected).Render(label))
+
+		if (i-first)%cols == cols-1 || i == last-1 {
+			b.WriteString("\n")
+		} else {
+			b.WriteString(strings.Repeat(" ", max(cellWidth-width, gridGap)))
+		}
+	}
+	return b.String()
+}
+
+// gridKey moves the selection across the grid for the arrow keys, and for
+// the vim keys hjkl when letters is set. It reports whether the key was
+// handled, which it never is in single-column layout.
+func (m *model) gridKey(key string, letters bool) bool {
+	cols := m.gridColumns()
+	if cols <= 1 {
+		return false
+	}
+	if !letters && len(key) == 1 {
+		return false
+	}
+
+	delta := 0
+	switch key {
+	case "left", "h":
+		delta = -1
+	case "right", "l":
+		delta = 1
+	case "up", "k":
+		delta = -cols
+	case "down", "j":
+		delta = cols
+	default:
+		return false
+	}
+
+	// Stay put at the edges of the grid
+	if i := m.list.Index() + delta; i >= 0 && i < len(m.filteredList) {
+		m.list.Select(i)
+	}
+	return true
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// projectInfo describes the project whose tasks are shown, for the header
+type projectInfo struct {
+	name   string // Directory of the root Taskfile
+	schema string // The Taskfile's version field, empty if missing
+}
+
+// rootTaskfile returns the absolute path of the root Taskfile: the first
+// one given with --taskfile, or else the one found. It is empty when there
+// is none.
+func rootTaskfile() string {
+	path := ""
+	if len(taskfiles) > 0 {
+		path = taskfiles[0]
+	} else if found, err := findTaskfile(); err == nil {
+		path = found
+	}
+	if path == "" {
+		return ""
+	}
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+	return path
+}
+
+// loadProjectInfo reads the project name and Taskfile version of the root
+// Taskfile
+func loadProjectInfo() projectInfo {
+	path := rootTaskfile()
+	info := projectInfo{name: filepath.Base(filepath.Dir(path))}
+	var taskfile struct {
+		Version interface{} `yaml:"version"`
+	}
+	if data, err := os.ReadFile(path); err == nil && yaml.Unmarshal(data, &taskfile) == nil && taskfile.Version != nil {
+		info.schema = fmt.Sprint(taskfile.Version)
+	}
+	return info
+}
+
+// headerView renders the project summary shown above the filter
+func (m model) headerView() string {
+	header := m.project.name
+	if m.project.schema != "" {
+		header += " • Taskfile v" + m.project.schema
+	}
+	header += fmt.Sprintf(" • %d tasks", len(m.allItems))
+	return m.currentTheme().selectedStyle().Padding(0, 1).Render(header)
+}
+
+// chromeHeight is the number of lines around the task list taken by the
+// header, filter, pinned preview and help text
+func (m model) chromeHeight() int {
+	if config.Header {
+		return 7 + m.pinnedHeight() + m.backgroundHeight()
+	}
+	return 6 + m.pinnedHeight() + m.backgroundHeight()
+}
@@ -0,0 +1,272 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// maxHistorySize is the size in bytes after which the history log is rotated
+const maxHistorySize = 1 << 20
+
+// historyEntry is one line of the history log
+type historyEntry struct {
+	Time     time.Time `json:"time"`
+	Task     string    `json:"task"`
+	Args     []string  `json:"args,omitempty"`
+	ExitCode int       `json:"exit_code"`
+	// Dir is the directory of the root Taskfile the task was run from, so
+	// runs in other projects can be told apart
+	Dir string `json:"dir,omitempty"`
+	// Scratch is set for ad-hoc commands, whose command line is in Task
+	Scratch bool `json:"scratch,omitempty"`
+}
+
+// historyClear controls whether 'gt history' wipes the log
+var historyClear bool
+
+// historyCmd prints the tasks that were run through gt
+var historyCmd = &cobra.Command{
+	Use:   "history",
+	Short: "Show the tasks you have run through gt",
+	Long: `Show a chronological log of the tasks run through gt, with the time,
+task name, arguments and exit code of each run.
+
+The log is stored as JSON lines in gt's config directory and is rotated
+once it grows past 1 MiB. Use --clear to remove it.`,
+	Example: `  gt history          # Show past runs
+  gt history --clear  # Remove the log`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if historyClear {
+			return clearHistory()
+		}
+
+		entries, err := readHistory()
+		if err != nil {
+			return err
+		}
+		printHistory(os.Stdout, entries)
+		return nil
+	},
+}
+
+// configDir returns the directory gt keeps its own files in
+func configDir() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "gt"), nil
+}
+
+// historyPath returns the location of the history log
+func historyPath() (string, error) {
+	dir, err := configDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "history.jsonl"), nil
+}
+
+// appendHistory adds an entry to the history log, rotating it when it grows
+// past maxHistorySize
+func appendHistory(entry historyEntry) error {
+	path, err := historyPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	if info, err := os.Stat(path); err == nil && info.Size() > maxHistorySize {
+		if err := os.Rename(path, path+".1"); err != nil {
+			return err
+		}
+	}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = f.Write(append(data, '\n'))
+	return err
+}
+
+// recordHistory appends a run to the history log. History is best effort,
+// so failures to write it never interrupt running tasks.
+func recordHistory(task string, args []string, err error) {
+	// Runs naming no task, like 'gt -l', can't be rerun from history
+	if task == "" {
+		return
+	}
+	appendHistory(historyEntry{
+		Time:     time.Now(),
+		Task:     task,
+		Args:     args,
+		ExitCode: exitCode(err),
+		Dir:      projectDir(),
+	})
+}
+
+// recordScratch appends a run of an ad-hoc command to the history log
+func recordScratch(command string, err error) {
+	appendHistory(historyEntry{
+		Time:     time.Now(),
+		Task:     command,
+		ExitCode: exitCode(err),
+		Dir:      projectDir(),
+		Scratch:  true,
+	})
+}
+
+// readHistory returns all entries in the history log, oldest first
+func readHistory() ([]historyEntry, error) {
+	path, err := historyPath()
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []historyEntry
+	for _, name := range []string{path + ".1", path} {
+		f, err := os.Open(name)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			var entry historyEntry
+			// Skip lines that were only partially written
+			if err := json.Unmarshal(scanner.Bytes(), &entry); err == nil {
+				entries = append(entries, entry)
+			}
+		}
+		f.Close()
+		if err := scanner.Err(); err != nil {
+			return nil, err
+		}
+	}
+
+	return entries, nil
+}
+
+// lastRunTask returns the most recently run of the given tasks in this
+// project according to the history log, or "" when none of them was run
+func lastRunTask(tasks []Task) string {
+	entries, err := readHistory()
+	if err != nil {
+		return ""
+	}
+	known := make(map[string]bool, len(tasks))
+	for _, task := range tasks {
+		known[task.Name] = true
+	}
+	dir := projectDir()
+	for i := len(entries) - 1; i >= 0; i-- {
+		if known[entries[i].Task] && !entries[i].Scratch && entries[i].Dir == dir {
+			return entries[i].Task
+		}
+	}
+	return ""
+}
+
+// projectDir returns the directory of the root Taskfile, which history
+// entries are recorded with
+func projectDir() string {
+	if path := rootTaskfile(); path != "" {
+		return filepath.Dir(path)
+	}
+	return ""
+}
+
+// clearHistory removes the history log and its rotated copy
+func clearHistory() error {
+	path, err := historyPath()
+	if err != nil {
+		return err
+	}
+	for _, name := range []string{path, path + ".1"} {
+		if err := os.Remove(name); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+	return nil
+}
+
+// printHistory writes the entries one per line
+func printHistory(w io.Writer, entries []historyEntry) {
+	for _, entry := range entries {
+		command := strings.TrimSpace(entry.Task + " " + strings.Join(entry.Args, " "))
+		if entry.Scratch {
+			command = "$ " + entry.Task
+		}
+		fmt.Fprintf(w, "%s  exit %-3d  %s\n", entry.Time.Local().Format("2006-01-02 15:04:05"), entry.ExitCode, command)
+	}
+}
+
+// taskValueFlags are the task flags followed by a value, which
+// splitTaskArgs must not take for a task name
+var taskValueFlags = map[string]bool{
+	"-d": true, "--dir": true,
+	"-t": true, "--taskfile": true,
+	"-o": true, "--output": true,
+	"-C": true, "--concurrency": true,
+	"-I": true, "--interval": true,
+	"--output-group-begin": true,
+	"--output-group-end":   true,
+	"--sort":               true,
+	"--remote-cache-dir":   true,
+	"--cache-expiry":       true,
+}
+
+// splitTaskArgs separates the first task name from the rest of the
+// arguments passed to task, skipping the values of task's flags
+func splitTaskArgs(args []string) (string, []string) {
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			break
+		}
+		if taskValueFlags[arg] {
+			i++
+			continue
+		}
+		if !strings.HasPrefix(arg, "-") && !strings.Contains(arg, "=") {
+			rest := append(append([]string{}, args[:i]...), args[i+1:]...)
+			return arg, rest
+		}
+	}
+	return "", args
+}
+
+// exitCode converts the error returned by running a command into an exit code
+func exitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	if exitErr, ok := err.(*exec.ExitError); ok {
+		return exitErr.ExitCode()
+	}
+	return 1
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestSplitTaskArgs(t *testing.T) {
+	tests := []struct {
+		args []string
+		name string
+		rest []string
+	}{
+		{[]string{"build"}, "build", []string{}},
+		{[]string{"-f", "build", "lint"}, "build", []string{"-f", "lint"}},
+		{[]string{"-d", "sub", "build"}, "build", []string{"-d", "sub"}},
+		{[]string{"--taskfile", "ci.yml", "test", "CI=1"}, "test", []string{"--taskfile", "ci.yml", "CI=1"}},
+		{[]string{"-o", "prefixed", "-C", "2", "lint"}, "lint", []string{"-o", "prefixed", "-C", "2"}},
+		{[]string{"--dir=sub", "build"}, "build", []string{"--dir=sub"}},
+		{[]string{"-l"}, "", []string{"-l"}},
+		{[]string{"-t", "ci.yml"}, "", []string{"-t", "ci.yml"}},
+		{[]string{"--", "build"}, "", []string{"--", "build"}},
+	}
+	for _, tt := range tests {
+		name, rest := splitTaskArgs(tt.args)
+		if name != tt.name || !slices.Equal(rest, tt.rest) {
+			t.Errorf("splitTaskArgs(%q) = %q, %q, want %q, %q", tt.args, name, rest, tt.name, tt.rest)
+		}
+	}
+}
+
+func TestRecordHistorySkipsRunsWithoutTask(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+
+	recordHistory("", []string{"-l"}, nil)
+	recordHistory("build", []string{"-f"}, errors.New("failed"))
+
+	entries, err := readHistory()
+	if err != nil {
+		t.Fatalf("readHistory() = %v", err)
+	}
+	if len(entries) != 1 || entries[0].Task != "build" {
+		t.Errorf("history = %+v, want only the build run", entries)
+	}
+}
+
+func TestLastRunTaskInProject(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+	tasks := []Task{{Name: "build"}, {Name: "test"}}
+
+	inProject(t, map[string]string{"Taskfile.yml": "version: '3'\n"})
+	recordHistory("build", nil, nil)
+	inProject(t, map[string]string{"Taskfile.yml": "version: '3'\n"})
+	recordHistory("test", nil, nil)
+	if got := lastRunTask(tasks); got != "test" {
+		t.Errorf("lastRunTask() = %q in the second project, want test", got)
+	}
+
+	inProject(t, map[string]string{"Taskfile.yml": "version: '3'\n"})
+	if got := lastRunTask(tasks); got != "" {
+		t.Errorf("lastRunTask() = %q in a project without runs, want none", got)
+	}
+}
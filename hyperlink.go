@@ -0,0 +1,52 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// hyperlinksSupported reports whether the terminal is known to render OSC 8
+// hyperlinks. GT_HYPERLINKS=1 or GT_HYPERLINKS=0 overrides the detection.
+func hyperlinksSupported() bool {
+	switch os.Getenv("GT_HYPERLINKS") {
+	case "1":
+		return true
+	case "0":
+		return false
+	}
+
+	switch os.Getenv("TERM_PROGRAM") {
+	case "iTerm.app", "WezTerm", "vscode", "Hyper", "ghostty":
+		return true
+	}
+	if os.Getenv("KITTY_WINDOW_ID") != "" || os.Getenv("WT_SESSION") != "" {
+		return true
+	}
+	if vte, err := strconv.Atoi(os.Getenv("VTE_VERSION")); err == nil && vte >= 5000 {
+		return true
+	}
+	return false
+}
+
+// taskURL builds the link target for a task's definition from the configured
+// format, replacing {path} and {line}
+func taskURL(task Task) string {
+	if task.Source == "" {
+		return ""
+	}
+	line := max(task.Line, 1)
+	return strings.NewReplacer(
+		"{path}", filepath.ToSlash(task.Source),
+		"{line}", strconv.Itoa(line),
+	).Replace(config.HyperlinkFormat)
+}
+
+// hyperlink wraps text in an OSC 8 escape sequence pointing at url
+func hyperlink(url, text string) string {
+	if url == "" {
+		return text
+	}
+	return "\x1b]8;;" + url + "\x1b\\" + text + "\x1b]8;;\x1b\\"
+}
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// include is an entry of a Taskfile's includes section
+type include struct {
+	namespace string
+	taskfile  string
+	optional  bool // Skip the include when the file doesn't exist
+	flatten   bool // Add the tasks without the namespace prefix
+	internal  bool // The tasks can't be run directly, so aren't listed
+	// dir is the directory the tasks run in, instead of the includer's
+	dir string
+}
+
+// parseIncludes reads the includes section of a Taskfile, sorted by
+// namespace. Entries are either a path or a map with a taskfile key.
+func parseIncludes(taskfile map[string]interface{}) ([]include, error) {
+	entries, _ := taskfile["includes"].(map[string]interface{})
+
+	var includes []include
+	for namespace, entry := range entries {
+		inc := include{namespace: namespace}
+		switch entry := entry.(type) {
+		case string:
+			inc.taskfile = entry
+		case map[string]interface{}:
+			inc.taskfile, _ = entry["taskfile"].(string)
+			inc.optional, _ = entry["optional"].(bool)
+			inc.flatten, _ = entry["flatten"].(bool)
+			inc.internal, _ = entry["internal"].(bool)
+			inc.dir, _ = entry["dir"].(string)
+		}
+		if inc.taskfile == "" {
+			return nil, fmt.Errorf("include %q has no taskfile", namespace)
+		}
+		includes = append(includes, inc)
+	}
+
+	sort.Slice(includes, func(i, j int) bool {
+		return includes[i].namespace < includes[j].namespace
+	})
+	return includes, nil
+}
+
+// resolveInclude returns the path of the Taskfile an include refers to,
+// relative to the directory of the including Taskfile. An include of a
+// directory refers to the Taskfile inside it.
+func resolveInclude(dir, taskfile string) (string, error) {
+	defer profiled("include resolution", time.Now())
+	path := taskfile
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(dir, path)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", err
+	}
+	if !info.IsDir() {
+		return path, nil
+	}
+
+	for _, name := range taskfileNames {
+		if _, err := os.Stat(filepath.Join(path, name)); err == nil {
+			return filepath.Join(path, name), nil
+		}
+	}
+	return "", fmt.Errorf("%s: %w", path, os.ErrNotExist)
+}
+
+// maxParallelParses bounds how many Taskfiles are read and parsed at once
+const maxParallelParses = 8
+
+// parseSlots holds a token for each Taskfile being read and parsed
+var parseSlots = make(chan struct{}, maxParallelParses)
+
+// includedTasks parses the Taskfiles included by the Taskfile at source,
+// concurrently. Missing optional includes are skipped, while missing
+// required ones and include cycles are errors, as they are for task.
+// ancestors holds the Taskfiles including this one, to detect cycles.
+func includedTasks(source string, taskfile map[string]interface{}, ancestors map[string]bool) ([]Task, error) {
+	includes, err := parseIncludes(taskfile)
+	if err != nil {
+		return nil, err
+	}
+
+	// Resolve the includes first, so errors are reported in include order
+	var kept []include
+	var paths []string
+	for _, inc := range includes {
+		path, err := resolveInclude(filepath.Dir(source), inc.taskfile)
+		if errors.Is(err, os.ErrNotExist) && inc.optional {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("include %q: %w", inc.namespace, err)
+		}
+		if inc.internal {
+			continue
+		}
+
+		path, err = filepath.Abs(path)
+		if err != nil {
+			return nil, err
+		}
+		if ancestors[path] {
+			return nil, fmt.Errorf("include %q: %s is already being included", inc.namespace, path)
+		}
+		debugf("include %q of %s: %s", inc.namespace, source, path)
+		kept = append(kept, inc)
+		paths = append(paths, path)
+	}
+
+	// Each include writes only its own slot, so no locking is needed
+	results := make([][]Task, len(kept))
+	errs := make([]error, len(kept))
+	var wg sync.WaitGroup
+	for i, path := range paths {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i], errs[i] = parseTaskfileTree(path, ancestors)
+		}()
+	}
+	wg.Wait()
+
+	var tasks []Task
+	for i, inc := range kept {
+		if errs[i] != nil {
+			return nil, fmt.Errorf("include %q: %w", inc.namespace, errs[i])
+		}
+		for _, task := range results[i] {
+			if !inc.flatten {
+				task.Name = inc.namespace + ":" + task.Name
+				aliases := make([]string, len(task.Aliases))
+				for j, alias := range task.Aliases {
+					aliases[j] = inc.namespace + ":" + alias
+				}
+				task.Aliases = aliases
+			}
+			task.WorkDir = includedWorkDir(inc.dir, task.WorkDir)
+			tasks = append(tasks, task)
+		}
+	}
+
+	return tasks, nil
+}
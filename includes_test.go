@@ -0,0 +1,129 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestMissingIncludes(t *testing.T) {
+	t.Run("optional", func(t *testing.T) {
+		found, err := parseTaskfileAt(filepath.Join("testdata", "includes", "optional", "Taskfile.yml"))
+		if err != nil {
+			t.Fatalf("a missing optional include failed parsing: %v", err)
+		}
+		if got, want := taskNames(found), []string{"build", "lib:hello"}; !slices.Equal(got, want) {
+			t.Errorf("tasks = %v, want %v", got, want)
+		}
+	})
+
+	t.Run("required", func(t *testing.T) {
+		_, err := parseTaskfileAt(filepath.Join("testdata", "includes", "required", "Taskfile.yml"))
+		if !errors.Is(err, fs.ErrNotExist) || !strings.Contains(err.Error(), `include "lib"`) {
+			t.Errorf("err = %v, want the missing include lib reported", err)
+		}
+	})
+}
+
+// writeIncludeTree writes a Taskfile including width Taskfiles, each of
+// which includes width more, down to depth levels, with tasks tasks in
+// each. It returns the path of the root Taskfile.
+func writeIncludeTree(b *testing.B, dir string, width, depth, tasks int) string {
+	var content strings.Builder
+	content.WriteString("version: '3'\n\n")
+	if depth > 0 {
+		content.WriteString("includes:\n")
+		for i := range width {
+			sub := filepath.Join(dir, fmt.Sprintf("ns%d", i))
+			if err := os.Mkdir(sub, 0o755); err != nil {
+				b.Fatal(err)
+			}
+			writeIncludeTree(b, sub, width, depth-1, tasks)
+			fmt.Fprintf(&content, "  ns%d: ./ns%d\n", i, i)
+		}
+	}
+	content.WriteString("\ntasks:\n")
+	for i := range tasks {
+		fmt.Fprintf(&content, "  task%d:\n    desc: Task %d\n    cmds:\n      - echo %d\n", i, i, i)
+	}
+
+	path := filepath.Join(dir, "Taskfile.yml")
+	if err := os.WriteFile(path, []byte(content.String()), 0o644); err != nil {
+		b.Fatal(err)
+	}
+	return path
+}
+
+// BenchmarkParseIncludeTree parses 73 Taskfiles of 50 tasks each, with the
+// includes parsed concurrently and, for comparison, one at a time
+func BenchmarkParseIncludeTree(b *testing.B) {
+	root := writeIncludeTree(b, b.TempDir(), 8, 2, 50)
+
+	for _, bench := range []struct {
+		name  string
+		slots int
+	}{
+		{"concurrent", maxParallelParses},
+		{"one at a time", 1},
+	} {
+		b.Run(bench.name, func(b *testing.B) {
+			saved := parseSlots
+			parseSlots = make(chan struct{}, bench.slots)
+			defer func() { parseSlots = saved }()
+
+			for b.Loop() {
+				if _, err := parseTaskfileAt(root); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func TestNamespacedDefaultTask(t *testing.T) {
+	found, err := parseTaskfileAt(filepath.Join("testdata", "includes", "default", "Taskfile.yml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Contains(taskNames(found), "docker:default") {
+		t.Fatalf("tasks = %v, want docker:default", taskNames(found))
+	}
+	if got := displayName("docker:default"); got != "docker:" {
+		t.Errorf("displayName(docker:default) = %q, want docker:", got)
+	}
+
+	saved := tasks
+	tasks = found
+	defer func() { tasks = saved }()
+	for _, name := range []string{"docker:", "docker:default"} {
+		if _, unknown := unknownTask([]string{name}); unknown {
+			t.Errorf("%s reported as unknown", name)
+		}
+	}
+}
+
+func TestTaskAliases(t *testing.T) {
+	found, err := parseTaskfileAt(filepath.Join("testdata", "includes", "aliases", "Taskfile.yml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	saved := tasks
+	tasks = found
+	defer func() { tasks = saved }()
+	for _, args := range [][]string{{"b"}, {"docker:p"}, {"-d", "sub", "b"}, {"--taskfile", "ci.yml", "docker:push"}} {
+		if name, unknown := unknownTask(args); unknown {
+			t.Errorf("unknownTask(%q) reported %s as unknown", args, name)
+		}
+	}
+	for _, args := range [][]string{{"p"}, {"-d", "sub", "x"}} {
+		if _, unknown := unknownTask(args); !unknown {
+			t.Errorf("unknownTask(%q) found a task", args)
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// lineNumber renders the number shown before the task at index when line
+// numbers are enabled, padded to line up for the whole list
+func (m model) lineNumber(index int) string {
+	if !config.LineNumbers {
+		return ""
+	}
+	width := len(strconv.Itoa(len(m.filteredList)))
+	return fmt.Sprintf("%*d ", width, index+1)
+}
+
+// jumpKey handles typing a line number in navigation mode, which moves the
+// selection to that task on enter. It reports whether the key was handled.
+func (m *model) jumpKey(key string) bool {
+	if !config.LineNumbers || m.filter.Focused() {
+		return false
+	}
+
+	switch {
+	case len(key) == 1 && key[0] >= '0' && key[0] <= '9':
+		m.jump += key
+		return true
+	case m.jump == "":
+		return false
+	case key == "backspace":
+		m.jump = m.jump[:len(m.jump)-1]
+		return true
+	case key == "enter":
+		if n, err := strconv.Atoi(m.jump); err == nil && n >= 1 && n <= len(m.filteredList) {
+			m.list.Select(n - 1)
+		}
+		m.jump = ""
+		return true
+	case key == "esc":
+		m.jump = ""
+		return true
+	}
+
+	// Any other key abandons the number and is handled as usual
+	m.jump = ""
+	return false
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/bubbles/key"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// keymapName is the keymap preset chosen with --keymap
+var keymapName string
+
+// keymap is a preset of navigation keys added to the default bindings. Each
+// key acts like the arrow key it maps to.
+type keymap struct {
+	name string
+	// always are remapped in every mode, so they must not be keys the
+	// filter needs for typing
+	always map[string]tea.KeyType
+	// navigation are only remapped in navigation mode, as they edit text
+	// while typing
+	navigation map[string]tea.KeyType
+	// help lists the preset's keys for the help line
+	help key.Binding
+}
+
+// keymaps are the presets, the first being the default
+var keymaps = []keymap{
+	{name: "default"},
+	{
+		name:   "vim",
+		always: map[string]tea.KeyType{"ctrl+j": tea.KeyDown},
+		// ctrl+k deletes to the end of the line while typing
+		navigation: map[string]tea.KeyType{"ctrl+k": tea.KeyUp},
+		help:       key.NewBinding(key.WithKeys("ctrl+j", "ctrl+k"), key.WithHelp("ctrl+j/ctrl+k", "navigate (ctrl+k outside the filter)")),
+	},
+	{
+		name:       "emacs",
+		always:     map[string]tea.KeyType{"ctrl+n": tea.KeyDown, "ctrl+p": tea.KeyUp},
+		navigation: map[string]tea.KeyType{"ctrl+f": tea.KeyRight, "ctrl+b": tea.KeyLeft},
+		help:       key.NewBinding(key.WithKeys("ctrl+n", "ctrl+p", "ctrl+f", "ctrl+b"), key.WithHelp("ctrl+n/ctrl+p/ctrl+f/ctrl+b", "navigate, into/out of deps")),
+	},
+}
+
+// activeKeymap is the preset in use
+var activeKeymap = keymaps[0]
+
+// setKeymap makes the preset called name active; empty means the default
+func setKeymap(name string) error {
+	if name == "" {
+		name = keymaps[0].name
+	}
+	for _, km := range keymaps {
+		if km.name == name {
+			activeKeymap = km
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown keymap %q, expected default, vim or emacs", name)
+}
+
+// translate returns the key the preset maps msg to, or msg itself. While
+// typing, only the keys that don't edit text are remapped.
+func (km keymap) translate(msg tea.KeyMsg, typing bool) tea.KeyMsg {
+	if key, ok := km.always[msg.String()]; ok {
+		return tea.KeyMsg{Type: key}
+	}
+	if key, ok := km.navigation[msg.String()]; ok && !typing {
+		return tea.KeyMsg{Type: key}
+	}
+	return msg
+}
@@ -0,0 +1,25 @@
+package main
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestVimKeymapLeavesCtrlKToTheFilter(t *testing.T) {
+	if err := setKeymap("vim"); err != nil {
+		t.Fatal(err)
+	}
+	defer setKeymap("")
+
+	ctrlK := tea.KeyMsg{Type: tea.KeyCtrlK}
+	if got := activeKeymap.translate(ctrlK, true); got.Type != tea.KeyCtrlK {
+		t.Errorf("ctrl+k while typing = %s, want it left for the filter", got)
+	}
+	if got := activeKeymap.translate(ctrlK, false); got.Type != tea.KeyUp {
+		t.Errorf("ctrl+k in navigation mode = %s, want up", got)
+	}
+	if got := activeKeymap.translate(tea.KeyMsg{Type: tea.KeyCtrlJ}, true); got.Type != tea.KeyDown {
+		t.Errorf("ctrl+j while typing = %s, want down", got)
+	}
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"reflect"
+
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// keyMap holds the TUI's key bindings in the task list. Update dispatches
+// on them and the help line is built from them, so the two can't drift
+// apart. While typing in the filter only the keys that don't type text
+// apply; see typingKey.
+type keyMap struct {
+	ForceQuit  key.Binding
+	Quit       key.Binding
+	Blur       key.Binding
+	Up         key.Binding
+	Down       key.Binding
+	Details    key.Binding
+	AllDescs   key.Binding
+	Group      key.Binding
+	Grid       key.Binding
+	Theme      key.Binding
+	Density    key.Binding
+	Reload     key.Binding
+	Case       key.Binding
+	Documented key.Binding
+	DepsLine   key.Binding
+	Source     key.Binding
+	Docs       key.Binding
+	Copy       key.Binding
+	Pin        key.Binding
+	Switch     key.Binding
+	HideDone   key.Binding
+	Yes        key.Binding
+	Verbose    key.Binding
+	More       key.Binding
+	Fewer      key.Binding
+	Stale      key.Binding
+	Mark       key.Binding
+	Namespace  key.Binding
+	SaveView   key.Binding
+	Select     key.Binding
+	RunHere    key.Binding
+	Background key.Binding
+	TaskList   key.Binding
+	Retry      key.Binding
+	Fold       key.Binding
+	Scratch    key.Binding
+	Filter     key.Binding
+}
+
+// keys are the bindings of the task list
+var keys = keyMap{
+	ForceQuit:  key.NewBinding(key.WithKeys("ctrl+c")),
+	Quit:       key.NewBinding(key.WithKeys("q", "esc"), key.WithHelp("q", "quit")),
+	Blur:       key.NewBinding(key.WithKeys("esc")),
+	Up:         key.NewBinding(key.WithKeys("up", "k"), key.WithHelp("↑/↓", "navigate")),
+	Down:       key.NewBinding(key.WithKeys("down", "j")),
+	Details:    key.NewBinding(key.WithKeys("tab"), key.WithHelp("tab", "toggle details (→: deps)")),
+	AllDescs:   key.NewBinding(key.WithKeys("shift+tab"), key.WithHelp("shift+tab", "all descriptions")),
+	Group:      key.NewBinding(key.WithKeys("ctrl+g"), key.WithHelp("ctrl+g", "group")),
+	Grid:       key.NewBinding(key.WithKeys("ctrl+l"), key.WithHelp("ctrl+l", "grid")),
+	Theme:      key.NewBinding(key.WithKeys("ctrl+t"), key.WithHelp("ctrl+t", "theme")),
+	Density:    key.NewBinding(key.WithKeys("="), key.WithHelp("=", "density")),
+	Reload:     key.NewBinding(key.WithKeys("ctrl+r"), key.WithHelp("ctrl+r", "reload")),
+	Case:       key.NewBinding(key.WithKeys("ctrl+s"), key.WithHelp("ctrl+s", "case")),
+	Documented: key.NewBinding(key.WithKeys("a"), key.WithHelp("a", "all/documented")),
+	DepsLine:   key.NewBinding(key.WithKeys("D"), key.WithHelp("D", "deps line")),
+	Source:     key.NewBinding(key.WithKeys("T"), key.WithHelp("T", "tasks from")),
+	Docs:       key.NewBinding(key.WithKeys("u"), key.WithHelp("u", "open docs")),
+	Copy:       key.NewBinding(key.WithKeys("c"), key.WithHelp("c", "copy task")),
+	Pin:        key.NewBinding(key.WithKeys("p"), key.WithHelp("p", "pin preview")),
+	Switch:     key.NewBinding(key.WithKeys("t"), key.WithHelp("t", "switch Taskfile")),
+	HideDone:   key.NewBinding(key.WithKeys("d"), key.WithHelp("d", "hide done")),
+	Yes:        key.NewBinding(key.WithKeys("y"), key.WithHelp("y", "auto-confirm")),
+	Verbose:    key.NewBinding(key.WithKeys("v"), key.WithHelp("v", "run verbose")),
+	More:       key.NewBinding(key.WithKeys("+"), key.WithHelp("+/-", "concurrency")),
+	Fewer:      key.NewBinding(key.WithKeys("-")),
+	Stale:      key.NewBinding(key.WithKeys("s"), key.WithHelp("s", "stale only")),
+	Mark:       key.NewBinding(key.WithKeys(" "), key.WithHelp("space", "mark")),
+	Namespace:  key.NewBinding(key.WithKeys("n"), key.WithHelp("n", "run namespace")),
+	SaveView:   key.NewBinding(key.WithKeys("w"), key.WithHelp("w", "save view")),
+	Select:     key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "select")),
+	RunHere:    key.NewBinding(key.WithKeys("o", "ctrl+o"), key.WithHelp("o", "run here")),
+	Background: key.NewBinding(key.WithKeys("b"), key.WithHelp("b", "run in background")),
+	TaskList:   key.NewBinding(key.WithKeys("L"), key.WithHelp("L", "task --list-all")),
+	Retry:      key.NewBinding(key.WithKeys("r")),
+	Fold:       key.NewBinding(key.WithKeys("f")),
+	Scratch:    key.NewBinding(key.WithKeys("!"), key.WithHelp("!", "run a command")),
+	Filter:     key.NewBinding(key.WithKeys("/")),
+}
+
+// typingKey reports whether key applies while typing in the filter, which
+// takes every key that types text
+func typingKey(key string) bool {
+	return len([]rune(key)) > 1
+}
+
+// matches reports whether msg is one of the binding's keys, leaving the
+// keys that type text to the filter while it is focused
+func (m model) matches(msg tea.KeyMsg, binding key.Binding) bool {
+	return key.Matches(msg, binding) && (!m.filter.Focused() || typingKey(msg.String()))
+}
+
+// all returns every binding in keys
+func (k keyMap) all() []key.Binding {
+	v := reflect.ValueOf(k)
+	bindings := make([]key.Binding, v.NumField())
+	for i := range bindings {
+		bindings[i] = v.Field(i).Interface().(key.Binding)
+	}
+	return bindings
+}
+
+// helpView renders the help line from the key bindings, with the current
+// setting of the toggles that cycle through several
+func (m model) helpView() string {
+	k := keys
+	k.Group.SetHelp("ctrl+g", "group ("+m.grouping.String()+")")
+	k.Theme.SetHelp("ctrl+t", "theme ("+m.currentTheme().name+")")
+	k.Density.SetHelp("=", "density ("+m.density.String()+")")
+	k.Case.SetHelp("ctrl+s", "case ("+m.caseMode()+")")
+	k.Source.SetHelp("T", "tasks from ("+m.sourceName()+")")
+	k.More.SetHelp("+/-", "concurrency ("+concurrencyName(m.concurrency)+")")
+
+	bindings := []key.Binding{
+		k.Up, k.Details, k.AllDescs, k.Group, k.Grid, k.Theme, k.Density,
+		k.Reload, k.Case, k.Documented, k.DepsLine, k.Source, k.Docs, k.Copy,
+		k.Pin, k.Switch, k.HideDone, k.Yes, k.Verbose, k.More, k.Stale, k.Mark,
+		k.Namespace, k.SaveView, k.Select, k.RunHere, k.Background, k.TaskList,
+		k.Quit,
+	}
+	if config.Scratch {
+		bindings = append(bindings, k.Scratch)
+	}
+	if activeKeymap.help.Enabled() {
+		bindings = append(bindings, activeKeymap.help)
+	}
+
+	h := help.New()
+	h.Styles.ShortKey = lipgloss.NewStyle()
+	h.Styles.ShortDesc = m.currentTheme().mutedStyle()
+	h.Styles.ShortSeparator = m.currentTheme().mutedStyle()
+	return h.ShortHelpView(bindings)
+}
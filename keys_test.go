@@ -0,0 +1,91 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// reservedKeys are handled before the bindings in keys: digits launch menu
+// tasks and jump to line numbers, and h, l and the left and right arrows
+// move across the grid and the deps
+var reservedKeys = strings.Fields("0 1 2 3 4 5 6 7 8 9 h l left right")
+
+func TestKeyBindingsDontCollide(t *testing.T) {
+	names := reflect.TypeOf(keys)
+	for _, typing := range []bool{false, true} {
+		bound := map[string]string{}
+		bind := func(key, name string) {
+			if typing && !typingKey(key) {
+				return
+			}
+			if other, ok := bound[key]; ok {
+				t.Errorf("%q is bound to both %s and %s (typing: %v)", key, other, name, typing)
+			}
+			bound[key] = name
+		}
+
+		for _, key := range reservedKeys {
+			bind(key, "reserved")
+		}
+		for i, binding := range keys.all() {
+			name := names.Field(i).Name
+			// esc blurs the filter while typing and quits otherwise
+			if typing && name == "Quit" || !typing && name == "Blur" {
+				continue
+			}
+			for _, key := range binding.Keys() {
+				bind(key, name)
+			}
+		}
+
+		// A keymap preset's keys would shadow the bindings
+		for _, km := range keymaps {
+			for key := range km.always {
+				if name, ok := bound[key]; ok {
+					t.Errorf("%s keymap's %q shadows %s (typing: %v)", km.name, key, name, typing)
+				}
+			}
+			for key := range km.navigation {
+				if name, ok := bound[key]; ok && !typing {
+					t.Errorf("%s keymap's %q shadows %s", km.name, key, name)
+				}
+			}
+		}
+	}
+}
+
+func TestHelpView(t *testing.T) {
+	m := loadedModel(t, []Task{{Name: "build"}}, 10)
+	help := m.helpView()
+	for _, want := range []string{"↑/↓ navigate", "ctrl+g group (flat)", "q quit"} {
+		if !strings.Contains(help, want) {
+			t.Errorf("help %q doesn't mention %q", help, want)
+		}
+	}
+	if strings.Contains(help, "run a command") {
+		t.Error("help mentions ! with scratch off")
+	}
+}
+
+func TestKeysStartTheFilter(t *testing.T) {
+	m := loadedModel(t, []Task{{Name: "build"}, {Name: "2fa"}}, 10)
+	m.menu = []Task{{Name: "build"}}
+
+	// A filter can start with a menu task's number
+	next, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("1")})
+	if got := next.(model).filter.Value(); got != "1" {
+		t.Errorf("typing 1 in the filter left %q", got)
+	}
+
+	// r and f start the filter with nothing to retry or fold
+	for _, key := range []string{"r", "f"} {
+		next, _ := m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+		next, _ = next.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)})
+		if got := next.(model); !got.filter.Focused() || got.filter.Value() != key {
+			t.Errorf("%s in navigation mode left the filter at %q, focused %v", key, got.filter.Value(), got.filter.Focused())
+		}
+	}
+}
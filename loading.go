@@ -0,0 +1,99 @@
+package main
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// tasksLoadedMsg carries the result of loading the tasks in the background
+type tasksLoadedMsg struct {
+	tasks   []Task
+	project projectInfo
+	lastRun string // Task to select first, the last one run
+	mtimes  fileTimes
+	listed  bool  // Whether the tasks are the ones task lists
+	listErr error // Why task couldn't list the tasks, if it was asked to
+	err     error
+}
+
+// loadTasks finds task and parses the Taskfile without blocking the TUI
+func loadTasks() tea.Msg {
+	if err := initialize(); err != nil {
+		return tasksLoadedMsg{err: err}
+	}
+	return tasksLoadedMsg{
+		tasks:   tasks,
+		project: loadProjectInfo(),
+		lastRun: lastRunTask(tasks),
+		mtimes:  modTimes(sessionTaskfiles(tasks)),
+	}
+}
+
+// tasksLoaded fills the list once loading has finished, or switches to the
+// error view if it failed
+func (m model) tasksLoaded(msg tasksLoadedMsg) (tea.Model, tea.Cmd) {
+	m.loading = false
+	if msg.err != nil {
+		m.err = msg.err
+		return m, nil
+	}
+
+	m.project = msg.project
+	firstLoad := m.allItems == nil
+	if msg.listErr != nil {
+		m.status = fmt.Sprintf("✗ task --list-all --json: %v", msg.listErr)
+	}
+	// Changes are tracked against tasks from the same source
+	if msg.listed != m.fromTask {
+		m.fromTask = msg.listed
+		m.baseline, m.changed = nil, nil
+	}
+	m.trackChanges(msg.tasks)
+	selected, _ := m.list.SelectedItem().(Task)
+	m.mtimes, m.outdated = msg.mtimes, false
+	m.infoAsked = false
+
+	var items []list.Item
+	for _, task := range msg.tasks {
+		items = append(items, task)
+	}
+	m.allItems = items
+
+	// Put the quick-launch menu tasks first
+	var skipped []string
+	m.menu, skipped = resolveMenu(config.Menu, msg.tasks)
+	if len(m.menu) > 0 {
+		m.allItems = menuFirst(items, m.menu)
+	}
+	if len(skipped) > 0 {
+		m.status = "menu: skipped unknown tasks: " + strings.Join(skipped, ", ")
+	}
+
+	m.applyFilter()
+
+	// Start on the task run last, so enter repeats it, and start watching
+	// the Taskfiles for changes
+	if firstLoad {
+		for i, item := range m.filteredList {
+			if item.(Task).Name == msg.lastRun {
+				m.list.Select(i)
+				break
+			}
+		}
+		return m, tea.Batch(m.askTaskInfo(), pollTaskfiles(slices.Collect(maps.Keys(m.mtimes))))
+	}
+
+	// Stay on the same task after reloading or swapping the source
+	for i, item := range m.filteredList {
+		if task, ok := item.(Task); ok && task.Name == selected.Name {
+			m.list.Select(i)
+			break
+		}
+	}
+	return m, m.askTaskInfo()
+}
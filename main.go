@@ -1,18 +1,30 @@
 package main
 
 import (
+	"bytes"
+	"context"
+	"errors"
 	"fmt"
+	"io"
+	"maps"
 	"os"
 	"os/exec"
+	"os/signal"
 	"path/filepath"
+	"slices"
 	"sort"
+	"strconv"
 	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
 
 	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/spinner"
 	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
-	"github.com/sahilm/fuzzy"
 	"github.com/spf13/cobra"
 	"gopkg.in/yaml.v3"
 )
@@ -23,11 +35,96 @@ type TaskCommand struct {
 	Args []string
 }
 
+// command builds the command that runs task with args, pointing it at
+// taskfile when one is given
+func (t TaskCommand) command(taskfile string, args ...string) *exec.Cmd {
+	full := append([]string{}, t.Args...)
+	if taskfile != "" {
+		full = append(full, "--taskfile", taskfile)
+	}
+	cmd := exec.Command(t.Cmd, append(full, args...)...)
+	if env := extraEnv(); len(env) > 0 {
+		cmd.Env = append(os.Environ(), env...)
+	}
+	return cmd
+}
+
+// extraEnv returns the variables set for task with the env config option
+// and --env, in that order so the flag wins
+func extraEnv() []string {
+	keys := make([]string, 0, len(config.Env))
+	for key := range config.Env {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var env []string
+	for _, key := range keys {
+		env = append(env, key+"="+config.Env[key])
+	}
+	return append(env, envFlags...)
+}
+
+// runOptions are the task flags gt adds when running tasks
+type runOptions struct {
+	yes         bool // --yes, so task answers the prompts itself
+	verbose     bool // --verbose, for task's own logging
+	concurrency int  // --concurrency, unless 0
+}
+
+// taskRunArgs returns the arguments that run the tasks in args, with the
+// flags of the run options before them
+func taskRunArgs(opts runOptions, args ...string) []string {
+	var flags []string
+	if opts.yes {
+		flags = append(flags, "--yes")
+	}
+	if opts.verbose {
+		flags = append(flags, "--verbose")
+	}
+	if opts.concurrency > 0 {
+		flags = append(flags, "--concurrency", strconv.Itoa(opts.concurrency))
+	}
+	return append(flags, args...)
+}
+
 // Task represents a task from the Taskfile
 type Task struct {
-	Name string
-	Desc string
-	Cmds []string // Added field for commands
+	Name string            `json:"name" yaml:"name"`
+	Desc string            `json:"desc,omitempty" yaml:"desc,omitempty"`
+	Cmds []string          `json:"cmds,omitempty" yaml:"cmds,omitempty"` // Added field for commands
+	Env  map[string]string `json:"env,omitempty" yaml:"env,omitempty"`
+	// Summary is the task's summary, which Desc falls back to, or
+	// replaces it with desc_field set to summary
+	Summary string `json:"summary,omitempty" yaml:"summary,omitempty"`
+	// Source is the path of the Taskfile the task was defined in
+	Source string `json:"source" yaml:"source"`
+	// Line is the line in Source where the task is defined
+	Line int `json:"line" yaml:"line"`
+	// Silent is set when task doesn't echo the task's commands
+	Silent bool `json:"silent,omitempty" yaml:"silent,omitempty"`
+	// Taskfile is passed to task with --taskfile when running the task. It is
+	// only set when Taskfiles were given explicitly with --taskfile.
+	Taskfile string `json:"taskfile,omitempty" yaml:"taskfile,omitempty"`
+	// Method is how task decides the task is up to date, empty if not set
+	Method    string   `json:"method,omitempty" yaml:"method,omitempty"`
+	Sources   []string `json:"sources,omitempty" yaml:"sources,omitempty"`
+	Generates []string `json:"generates,omitempty" yaml:"generates,omitempty"`
+	// Deps are the names of the tasks run before this one, as written in
+	// the Taskfile
+	Deps []string `json:"deps,omitempty" yaml:"deps,omitempty"`
+	// Aliases are the other names task runs the task by
+	Aliases []string `json:"aliases,omitempty" yaml:"aliases,omitempty"`
+	// Tags are the tags found in Desc, like ci for "[ci] Run the linters"
+	Tags []string `json:"tags,omitempty" yaml:"tags,omitempty"`
+	// Dir is the task's dir as written in the Taskfile, empty when it
+	// inherits the directory of its Taskfile
+	Dir string `json:"dir,omitempty" yaml:"dir,omitempty"`
+	// WorkDir is the absolute directory the task runs in
+	WorkDir string `json:"work_dir,omitempty" yaml:"work_dir,omitempty"`
+	// Info is what task itself reports about the task, loaded in the
+	// background by the TUI when task can list tasks as JSON
+	Info *TaskInfo `json:"info,omitempty" yaml:"info,omitempty"`
 }
 
 // Implement list.Item interface
@@ -38,6 +135,11 @@ func (t Task) FilterValue() string { return t.Name }
 var (
 	taskCmd TaskCommand
 	tasks   []Task
+
+	// Memoized result of findTaskCommand, since probing 'go tool task' is slow
+	taskCmdOnce   sync.Once
+	taskCmdResult TaskCommand
+	taskCmdErr    error
 )
 
 // Model represents the TUI state
@@ -51,11 +153,88 @@ type model struct {
 	width        int
 	height       int
 	expanded     bool // Combined state for showing desc and cmds
+	grouping     groupMode
+	documented   bool            // Only show tasks that have a description
+	run          *taskRun        // Task running with output captured in the TUI
+	output       viewport.Model  // Scrollable view of the captured output
+	status       string          // Result of the last captured run
+	staleOnly    bool            // Only show tasks that are not up to date
+	upToDate     map[string]bool // Results of 'task --status' by taskID, nil until checked
+	checking     bool            // Whether status checks are in progress
+	totalMatches int             // Number of matches before capping to config.MaxResults
+	menu         []Task          // Quick-launch tasks, run with the keys 1-9
+	hyperlinks   bool            // Whether task names are rendered as OSC 8 links
+	failed       *Task           // Last task that failed, offered for retry
+	grid         bool            // Flow task names across columns on wide terminals
+	marked       []Task          // Tasks marked with space to run together
+	theme        int             // Index of the active preset in themes
+	picked       []string        // Tasks chosen with --print-selection
+	ran          map[string]bool // taskIDs of the tasks run during this session
+	hideRan      bool            // Hide tasks already run while the filter is empty
+	loading      bool            // Whether the tasks are still being loaded
+	spinner      spinner.Model   // Shown while loading
+	jump         string          // Line number being typed in navigation mode
+	yes          bool            // Pass --yes so task's prompts are confirmed
+	verbose      bool            // Pass --verbose for task's own logging
+	matchCase    bool            // Filter case-sensitively
+	allDescs     bool            // Show every task's description on a second line
+	copying      *Task           // Task being copied while its new name is entered
+	copyName     textinput.Model // Name of the copy
+	filterErr    error           // Why the filter is invalid, e.g. a bad regexp
+	project      projectInfo     // Shown in the header
+	depFocus     bool            // Whether the details view's dependency list is focused
+	dep          int             // Dependency chosen in the details view
+	lastElapsed  time.Duration   // How long the last foreground run took
+	pinned       *Task           // Task whose details stay shown while browsing
+	confirmQuit  bool            // Whether quitting waits for confirmation
+	switching    []string        // Taskfiles offered to switch to, while picking one
+	switchIndex  int             // Taskfile chosen in the picker
+	switchRoot   string          // Directory the Taskfiles were found in
+	scratching   bool            // Whether an ad-hoc command is being entered
+	scratchCmd   textinput.Model // Ad-hoc command to run with the shell
+	savingView   bool            // Whether the name of a view to save is being entered
+	viewInput    textinput.Model // Name to save the current filter under
+	unfolded     map[string]bool // Tasks whose long command lists are shown in full
+	depChips     bool            // Show the selected task's deps on a line below it
+	mtimes       fileTimes       // When the Taskfiles were modified as the tasks were loaded
+	outdated     bool            // Whether a Taskfile changed since the tasks were loaded
+	baseline     taskDefinitions // Task definitions when the session started
+	changed      map[string]bool // Tasks changed or added since then
+	background   []*bgRun        // Tasks started with b, listed until shortly after they end
+	fromTask     bool            // List the tasks task reports instead of the parsed ones
+	concurrency  int             // Limit on the tasks task runs in parallel, 0 for none
+	density      density         // Room each task takes in the list
+	infoAsked    bool            // Whether task was asked for the tasks' metadata since they were loaded
+}
+
+// groupMode controls how tasks are grouped in the list
+type groupMode int
+
+const (
+	groupFlat groupMode = iota
+	groupByNamespace
+	groupByFile
+)
+
+func (g groupMode) String() string {
+	switch g {
+	case groupByNamespace:
+		return "namespace"
+	case groupByFile:
+		return "file"
+	default:
+		return "flat"
+	}
+}
+
+// next returns the grouping mode that follows g in the cycle
+func (g groupMode) next() groupMode {
+	return (g + 1) % 3
 }
 
 // rootCmd represents the base command when called without any subcommands
 var rootCmd = &cobra.Command{
-	Use:   "gt [task_name] [flags]",
+	Use:   "gt [gt flags --] [task_name] [flags]",
 	Short: "Interactive wrapper for Go Task",
 	Long: `An interactive CLI wrapper for Go Task that provides a 
 fuzzy-searchable interface for your Taskfile tasks.
@@ -63,65 +242,539 @@ fuzzy-searchable interface for your Taskfile tasks.
 When run without arguments, launches an interactive TUI.
 When run with arguments, passes them directly to task.
 
+gt's own commands, such as list, serve or edit, only run when they are the
+first argument, and a task of the same name in the Taskfile takes
+precedence: with a task called serve, 'gt serve' runs it. 'gt -- name'
+always passes name to task.
+
+To combine gt's own flags with task arguments, put gt's flags before the
+task name (gt --log-file out.txt build). gt picks its flags out of those
+before the first task name or '--', passing the others on to task, and
+everything from the task name on goes to task unchanged, so task's own
+'--' for CLI_ARGS keeps working (gt build -- args). A '--' before any
+task flag ends gt's flags, passing everything after it to task verbatim.
+-i and -t too are only gt's before the task name, so 'gt build -i' passes
+-i on to task.
+
 Examples:
   gt                  # Launch interactive TUI
   gt build            # Run the 'build' task
   gt -l               # List all available tasks
+  gt -l --json        # Have task list the tasks as JSON
   gt clean test       # Run 'clean' and then 'test' tasks
-`,
+  gt --log-file out.txt -- build --force
+                      # Run 'build --force', copying its output to out.txt
+  gt --continue-on-error -- lint test
+                      # Run 'lint' and 'test', even if 'lint' fails
+  gt -i docker        # Launch the TUI filtered to "docker"
+  gt --print-resolved --json
+                      # Show the tasks gt parsed as JSON
+  gt --env SHELL=/bin/bash -- build
+                      # Run 'build' with an extra environment variable
+  gt --print-cmd -- build
+                      # Show the command gt would run for 'build'
+  gt -t Taskfile.yml -t Taskfile.local.yml
+                      # Browse the tasks of both Taskfiles together
+
+With --print-selection the TUI opens with any arguments as the initial
+filter, as with -i. It draws on stderr and running a task prints
+its name to stdout instead, one name per line when several are marked, and
+exits with status 0. Nothing else is written to stdout. Quitting without
+choosing a task exits with status 130. This lets shell functions decide
+what to do with the selection, e.g. task $(gt --print-selection).
+
+Tasks with a prompt: wait for confirmation before running. --yes (-y) is
+passed on to task, which then answers every prompt with yes. In the TUI, y
+toggles this for the tasks run from it.
+
+With --safe (or --read-only) tasks can be browsed but not run, nor copied;
+--print-selection still prints the chosen tasks.
+
+With --global-search the tasks of every Taskfile in the git repository
+that isn't ignored are listed together, tagged with their Taskfile, and run
+from their Taskfile's directory. The Taskfiles found are reused for an hour.
+
+With --filter-unknown (or filter_unknown in the config file), a task name
+that doesn't exist opens the TUI filtered with it, to pick the intended
+task instead.
+
+With --simple, or when stdin or the screen isn't a terminal, the tasks
+are listed with numbers instead, filtered by any arguments as with -i, and
+the number of the task to run is read from stdin.
+
+In the TUI, w saves the filter under a name, along with the documented,
+grouping and case toggles, and --view opens the TUI with it again. Views
+are kept in views.json in gt's config directory.
+
+gt's own flags come first, before the task name. Flags gt doesn't know,
+such as -f (--force) or -w (--watch), are passed on to task. gt claims
+these short flags:
+
+  -i  --interactive, so task's -i (--init) is available as 'gt init'
+  -t  --taskfile, like task's, but repeatable to merge Taskfiles
+  -y  --yes, passed on to task
+  -l  --list and -a --list-all, listing the tasks like task does; along
+      with task flags, like 'gt -l --json', they go to task as --list
+      and --list-all
+
+Every other short flag, and anything after the task name, goes to task.
+
+Tasks run through gt exit with task's own status. gt's own failures exit
+with these statuses:
+
+` + exitCodeTable(),
 	// We don't want cobra's argument validation since we're passing everything to task
 	DisableFlagParsing: true,
+	Args:               cobra.ArbitraryArgs,
 	Run: func(cmd *cobra.Command, args []string) {
-		// If args are provided, pass them directly to task
-		if len(args) > 0 {
-			os.Exit(runTaskDirect(args))
+		// Some flags are recognized anywhere before the task name
+		args = extractEarlyFlags(cmd, args)
+
+		// Parse gt's own flags, which must come before a '--' separator
+		gtArgs, taskArgs := splitArgs(cmd, args)
+		if err := cmd.Flags().Parse(gtArgs); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if help, _ := cmd.Flags().GetBool("help"); help {
+			cmd.Help()
+			return
+		}
+		for _, env := range envFlags {
+			if key, _, ok := strings.Cut(env, "="); !ok || key == "" {
+				fmt.Fprintf(os.Stderr, "Error: --env %q is not KEY=VALUE\n", env)
+				os.Exit(1)
+			}
+		}
+		if cmd.Flags().Changed("concurrency") {
+			if err := validateConcurrency(concurrency); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+		}
+		if keymapName != "" {
+			if err := setKeymap(keymapName); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: --keymap: %v\n", err)
+				os.Exit(1)
+			}
+		}
+		taskArgs = append(cmd.Flags().Args(), taskArgs...)
+		// --json is only gt's with --print-resolved; task has its own
+		if jsonOutput && !printResolved {
+			jsonOutput = false
+			taskArgs = append([]string{"--json"}, taskArgs...)
+		}
+		startDebugLog(os.Stderr)
+		debugf("concurrency: %s", concurrencyName(concurrency))
+		if globalSearch {
+			if found := globalTaskfiles(); len(found) > 0 {
+				taskfiles, keepDuplicates = found, true
+			}
+		}
+
+		if showVersion {
+			printVersion()
+			return
+		}
+
+		if showTaskResolution {
+			printTaskResolution(os.Stdout)
+			return
+		}
+
+		// -l and -a list the tasks, unless there are task flags for task
+		// to list them with
+		if (listTasks || listAllTasks) && len(taskArgs) == 0 {
+			mustInitialize()
+			printTaskList(os.Stdout, tasks, listAllTasks)
+			return
+		}
+		if listAllTasks {
+			taskArgs = append([]string{"--list-all"}, taskArgs...)
+		} else if listTasks {
+			taskArgs = append([]string{"--list"}, taskArgs...)
+		}
+
+		// With --interactive the arguments become the initial filter. The
+		// TUI loads the tasks itself so it can show progress.
+		if interactive || printSelection {
+			os.Exit(launchTUI(strings.Join(taskArgs, " ")))
+		}
+		if len(taskArgs) == 0 && !printResolved {
+			os.Exit(launchTUI(""))
+		}
+
+		mustInitialize()
+
+		// Dump what gt parsed instead of running anything
+		if printResolved {
+			if err := writeResolved(os.Stdout, tasks, jsonOutput); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			return
+		}
+
+		// Show the command instead of running it
+		if printCmd {
+			if err := writeCommands(os.Stdout, taskArgs); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
 			return
 		}
 
-		// Otherwise, start the TUI
-		launchTUI()
+		// Offer the TUI to pick the intended task instead of letting task
+		// fail on a mistyped name
+		if filterUnknown || config.FilterUnknown {
+			if name, ok := unknownTask(taskArgs); ok {
+				os.Exit(launchTUI(unknownTaskFilter(tasks, name)))
+			}
+		}
+
+		// Pass the arguments directly to task
+		code := runTaskDirect(taskArgs)
+
+		// Open the TUI on the failed task to rerun it or pick a related one.
+		// gt still exits with the status of the failed run, whatever is
+		// picked.
+		if code != 0 && tuiOnFail {
+			name, _ := splitTaskArgs(taskArgs)
+			launchTUI(name)
+		}
+		os.Exit(code)
+	},
+}
+
+// tuiOnFail launches the TUI filtered with the task name when running it
+// from the command line fails
+var tuiOnFail bool
+
+// filterUnknown launches the TUI filtered with the task name when the task
+// to run doesn't exist
+var filterUnknown bool
+
+// excludeFlags are the task name patterns given with --exclude
+var excludeFlags []string
+
+// showAll ignores the exclude patterns
+var showAll bool
+
+// envFlags are the KEY=VALUE variables given with --env
+var envFlags []string
+
+// safeMode lets tasks be browsed but not run
+var safeMode bool
+
+// assumeYes confirms the prompts of tasks by passing --yes to task
+var assumeYes bool
+
+// verbose runs tasks with task's --verbose logging
+var verbose bool
+
+// caseSensitive makes the filter match case; ctrl+s toggles it in the TUI
+var caseSensitive bool
+
+// interactive forces the TUI to launch even when arguments are given
+var interactive bool
+
+// taskfiles are the Taskfiles given with --taskfile, merged into one list
+var taskfiles []string
+
+// keepDuplicates keeps tasks with the same name from different Taskfiles
+var keepDuplicates bool
+
+// extractEarlyFlags removes -i/--interactive and -t/--taskfile from the
+// flags before the first task name or '--' and records them. Since flag
+// parsing is disabled for the root command they have to be detected by
+// hand. Flags after the task name are left for task, so 'gt build -i'
+// passes -i on.
+func extractEarlyFlags(cmd *cobra.Command, args []string) []string {
+	var rest []string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "--" || !strings.HasPrefix(arg, "-"):
+			return append(rest, args[i:]...)
+		case arg == "-i" || arg == "--interactive":
+			interactive = true
+		case (arg == "-t" || arg == "--taskfile") && i+1 < len(args):
+			i++
+			taskfiles = append(taskfiles, args[i])
+		case strings.HasPrefix(arg, "--taskfile="):
+			taskfiles = append(taskfiles, strings.TrimPrefix(arg, "--taskfile="))
+		case (taskValueFlags[arg] || isGtFlag(cmd, arg) && takesValue(cmd, arg)) && i+1 < len(args):
+			// Skip the flag's value, which isn't a task name
+			i++
+			rest = append(rest, arg, args[i])
+		default:
+			rest = append(rest, arg)
+		}
+	}
+	return rest
+}
+
+// isGtFlag reports whether arg is one of the command's own flags
+func isGtFlag(cmd *cobra.Command, arg string) bool {
+	if !strings.HasPrefix(arg, "-") || arg == "-" {
+		return false
+	}
+	name, _, _ := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+	if strings.HasPrefix(arg, "--") {
+		return cmd.Flags().Lookup(name) != nil
+	}
+	return len(name) == 1 && cmd.Flags().ShorthandLookup(name) != nil
+}
+
+// logFile is an optional file that task output is copied to
+var logFile string
+
+// quiet suppresses the result summary printed after running a task
+var quiet bool
+
+// retries is how many times a failing task is re-run when passing through
+var retries int
+
+// splitArgs peels gt's own flags off the front of the arguments, apart
+// from the arguments for task. Flags gt doesn't know are forwarded to task,
+// and so is everything from the first argument that isn't a flag. A '--'
+// ends gt's flags, and is dropped unless task flags came before it.
+func splitArgs(cmd *cobra.Command, args []string) ([]string, []string) {
+	var gtArgs, taskArgs []string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "--" && len(taskArgs) == 0:
+			return gtArgs, args[i+1:]
+		case isGtFlag(cmd, arg):
+			gtArgs = append(gtArgs, arg)
+			if takesValue(cmd, arg) && i+1 < len(args) {
+				i++
+				gtArgs = append(gtArgs, args[i])
+			}
+		case strings.HasPrefix(arg, "-") && arg != "-" && arg != "--":
+			taskArgs = append(taskArgs, arg)
+		default:
+			return gtArgs, append(taskArgs, args[i:]...)
+		}
+	}
+	return gtArgs, taskArgs
+}
+
+// takesValue reports whether the gt flag arg is followed by its value
+func takesValue(cmd *cobra.Command, arg string) bool {
+	if strings.Contains(arg, "=") {
+		return false
+	}
+	name := strings.TrimLeft(arg, "-")
+	flag := cmd.Flags().Lookup(name)
+	if !strings.HasPrefix(arg, "--") {
+		flag = cmd.Flags().ShorthandLookup(name)
+	}
+	return flag != nil && flag.NoOptDefVal == ""
+}
+
+// listTasks and listAllTasks print gt's task listing for -l and -a, the
+// short flags task lists its tasks with
+var listTasks, listAllTasks bool
+
+// listAll controls whether 'gt list' includes tasks without a description
+var listAll bool
+
+// listCmd prints the tasks found in the Taskfile
+var listCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List tasks from the Taskfile",
+	Long: `List tasks from the Taskfile.
+
+Like 'task --list', only tasks with a description are shown by default.
+Use --all to include every task, like 'task --list-all'.`,
+	Example: `  gt list             # Tasks with a description
+  gt list --all       # Every task`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		mustInitialize()
+		printTaskList(os.Stdout, tasks, listAll)
 	},
 }
 
 func main() {
-	cobra.OnInitialize(initialize)
+	var err error
+	if config, err = loadConfig(); err != nil {
+		// A broken config shouldn't lock anyone out of their tasks
+		fmt.Fprintf(os.Stderr, "gt: ignoring the config file, using the defaults: %v\n", err)
+		config = resetConfig()
+	}
+
+	rootCmd.Flags().StringVar(&logFile, "log-file", "", "Also write task output to this file")
+	rootCmd.Flags().BoolVarP(&interactive, "interactive", "i", false, "Launch the TUI, using any arguments as the initial filter")
+	rootCmd.Flags().StringArrayVarP(&taskfiles, "taskfile", "t", nil, "Taskfile to use; repeat to merge several into one list")
+	rootCmd.Flags().BoolVar(&keepDuplicates, "keep-duplicates", false, "With several --taskfile, keep tasks with the same name from each file")
+	rootCmd.Flags().BoolVar(&echoTasks, "echo", false, "Print a banner to stderr before each task, running the tasks one by one")
+	rootCmd.Flags().BoolVar(&continueOnError, "continue-on-error", false, "When running several tasks, run each on its own and keep going after failures")
+	rootCmd.Flags().BoolVar(&printResolved, "print-resolved", false, "Print the tasks gt parsed, after merging Taskfiles, and exit")
+	rootCmd.Flags().BoolVar(&jsonOutput, "json", false, "With --print-resolved, print JSON instead of YAML; passed on to task otherwise")
+	rootCmd.Flags().BoolVar(&showVersion, "version", false, "Print gt's version and the version of task it uses")
+	rootCmd.Flags().BoolVar(&showTaskResolution, "show-task-resolution", false, "Show which task binaries were found, their versions and which one is used")
+	rootCmd.Flags().BoolVar(&printSelection, "print-selection", false, "Print the task names chosen in the TUI instead of running them")
+	rootCmd.Flags().BoolVar(&printCmd, "print-cmd", false, "Print the task command gt would run, shell-quoted, instead of running it")
+	rootCmd.Flags().BoolVarP(&listTasks, "list", "l", false, "List the tasks with a description, like 'gt list'")
+	rootCmd.Flags().BoolVarP(&listAllTasks, "list-all", "a", false, "List every task, like 'gt list --all'")
+	rootCmd.Flags().BoolVarP(&assumeYes, "yes", "y", false, "Pass --yes to task so prompts are confirmed; y toggles this in the TUI")
+	rootCmd.Flags().StringArrayVar(&envFlags, "env", nil, "Set an environment variable (KEY=VALUE) for task; repeatable")
+	rootCmd.Flags().BoolVar(&emitEvents, "events", false, "Write task-selected, task-started and task-finished events as JSON lines to stderr, after the TUI closes when it is used")
+	rootCmd.Flags().StringArrayVar(&excludeFlags, "exclude", nil, "Hide tasks whose names match this glob pattern, e.g. 'ci:*'; repeatable")
+	rootCmd.Flags().BoolVar(&showAll, "show-all", false, "Show tasks hidden by --exclude or the exclude config option")
+	rootCmd.Flags().BoolVar(&safeMode, "safe", false, "Browse tasks without being able to run them")
+	rootCmd.Flags().BoolVar(&safeMode, "read-only", false, "Same as --safe")
+	rootCmd.Flags().BoolVar(&quiet, "quiet", false, "Don't print how long the task took")
+	rootCmd.Flags().IntVar(&concurrency, "concurrency", 0, "Pass --concurrency to task to limit how many tasks run in parallel; +/- change it in the TUI")
+	rootCmd.Flags().IntVar(&retries, "retries", 0, "Re-run a failing task up to this many times, with backoff")
+	rootCmd.Flags().BoolVar(&verbose, "verbose", false, "Pass --verbose to task for its own logging; v runs a task this way in the TUI")
+	rootCmd.Flags().BoolVar(&globalSearch, "global-search", false, "List the tasks of every Taskfile in the repository, found once an hour")
+	rootCmd.Flags().StringVar(&keymapName, "keymap", "", "Navigation keys added to the defaults: default, vim (ctrl+j/ctrl+k) or emacs (ctrl+n/ctrl+p/ctrl+f/ctrl+b)")
+	rootCmd.Flags().StringVar(&viewName, "view", "", "Open the TUI with a filter saved with w in the TUI")
+	rootCmd.Flags().BoolVar(&caseSensitive, "case-sensitive", false, "Match the filter against task names case-sensitively; ctrl+s toggles this in the TUI")
+	rootCmd.Flags().BoolVar(&debug, "debug", false, "Log what gt does, such as the Taskfiles and task binary it uses, to stderr")
+	rootCmd.Flags().BoolVar(&profile, "profile", false, "Log how long finding and parsing the Taskfiles took to stderr")
+	rootCmd.Flags().BoolVar(&autoRun, "auto-run", false, "Run the task the filter narrows down to once typing pauses, except for tasks like deploy or clean (see auto_run_exclude)")
+	rootCmd.Flags().BoolVar(&simplePicker, "simple", false, "Pick the task from a numbered list instead of the TUI; used when not on a terminal")
+	rootCmd.Flags().BoolVar(&tuiOnFail, "tui-on-fail", false, "When the task fails, open the TUI filtered with its name to rerun it or pick another")
+	rootCmd.Flags().BoolVar(&filterUnknown, "filter-unknown", false, "When the task to run doesn't exist, open the TUI filtered with its name instead")
+
+	listCmd.Flags().BoolVarP(&listAll, "all", "a", false, "Include tasks without a description")
+	rootCmd.AddCommand(listCmd)
+
+	historyCmd.Flags().BoolVar(&historyClear, "clear", false, "Remove the history log")
+	rootCmd.AddCommand(historyCmd)
+
+	exportCmd.Flags().StringVar(&exportFormat, "format", "md", "Output format: md or csv")
+	exportCmd.Flags().BoolVarP(&exportAll, "all", "a", false, "Include tasks without a description")
+	exportCmd.Flags().BoolVar(&exportCmds, "cmds", false, "Add each task's commands")
+	rootCmd.AddCommand(exportCmd)
+
+	serveCmd.Flags().StringVar(&serveSocket, "socket", "", "Path of the unix socket to listen on")
+	rootCmd.AddCommand(serveCmd)
+
+	rootCmd.AddCommand(runCmd, validateCmd, describeCmd, initCmd, doctorCmd, editCmd)
+	dropShadowedCommands(rootCmd, os.Args[1:])
 
 	if err := rootCmd.Execute(); err != nil {
-		fmt.Println(err)
+		fmt.Fprintln(os.Stderr, err)
 		os.Exit(1)
 	}
 }
 
-// initialize runs before command execution
-func initialize() {
+// Errors reported during startup
+var (
+	ErrTaskNotFound = errors.New("task command not found in PATH")
+	ErrNoTaskfile   = errors.New("no Taskfile found, looked for " + strings.Join(searchedTaskfileNames, ", "))
+	ErrNoTasks      = errors.New("no tasks found in Taskfile")
+)
+
+// initialize locates the task binary and loads the tasks from the Taskfile
+func initialize() error {
+	start := time.Now()
+	defer func() { reportProfile(time.Since(start)) }()
+
 	var err error
 	// Check if task is available
 	taskCmd, err = findTaskCommand()
 	if err != nil {
-		fmt.Println("Error: Task is not installed")
-		fmt.Println("Please install Go Task:")
-		fmt.Println("- Official repository: https://github.com/go-task/task")
-		fmt.Println("- Installation guide: https://taskfile.dev/installation/")
-		os.Exit(1)
+		return err
+	}
+	debugf("task command: %s", strings.Join(append([]string{taskCmd.Cmd}, taskCmd.Args...), " "))
+	profiled("task binary", start)
+
+	// Parse the Taskfiles given on the command line, or find the Taskfile
+	paths := taskfiles
+	if len(taskfiles) > 0 {
+		tasks, err = parseTaskfiles(taskfiles, keepDuplicates)
+	} else {
+		var path string
+		tasks, path, err = parseTaskfile()
+		paths = []string{path}
+		// task won't find an extensionless Taskfile, so it is passed with
+		// --taskfile as if it was given on the command line
+		if isPlainTaskfile(path) {
+			taskfiles = paths
+		}
 	}
-	// Parse Taskfile
-	tasks, err = parseTaskfile()
 	if err != nil {
-		fmt.Printf("Error parsing Taskfile: %v\n", err)
-		os.Exit(1)
+		return err
 	}
+	debugf("parsed %d tasks", len(tasks))
 	if len(tasks) == 0 {
-		fmt.Println("No tasks found in Taskfile. Please make sure your Taskfile has tasks defined.")
-		os.Exit(1)
+		return explainNoTasks(paths)
+	}
+
+	// Hide excluded tasks everywhere, unless --show-all overrides it
+	if !showAll {
+		patterns := append(append([]string{}, config.Exclude...), excludeFlags...)
+		parsed := len(tasks)
+		if tasks, err = excludeTasks(tasks, patterns); err != nil {
+			return err
+		}
+		if len(tasks) == 0 {
+			return allExcluded(parsed, patterns)
+		}
+		if len(patterns) > 0 {
+			debugf("%d tasks left after excluding %s", len(tasks), strings.Join(patterns, ", "))
+		}
 	}
 
 	// Sort tasks alphabetically by name
 	sortTasksByName(tasks)
+	return nil
+}
+
+// mustInitialize runs initialize and exits with a friendly message on failure
+func mustInitialize() {
+	if err := initialize(); err != nil {
+		reportInitError(err)
+		os.Exit(initExitCode(err))
+	}
+}
+
+// reportInitError prints a user-facing explanation for a startup error to
+// stderr, keeping stdout for output scripts read, like --print-selection's
+func reportInitError(err error) {
+	fmt.Fprintln(os.Stderr, initErrorMessage(err))
+}
+
+// initErrorMessage explains an error returned by initialize
+func initErrorMessage(err error) string {
+	switch {
+	case errors.Is(err, ErrTaskNotFound):
+		return "Error: Task is not installed\n" +
+			"Please install Go Task:\n" +
+			"- Official repository: https://github.com/go-task/task\n" +
+			"- Installation guide: https://taskfile.dev/installation/"
+	case errors.Is(err, ErrNoTaskfile):
+		return "No Taskfile found in this directory or its parents. Create one with 'gt init'."
+	case errors.Is(err, ErrNoTasks):
+		var noTasks *noTasksError
+		if errors.As(err, &noTasks) {
+			return "No tasks found: " + noTasks.reason + "."
+		}
+		return "No tasks found in Taskfile. Please make sure your Taskfile has tasks defined."
+	default:
+		return fmt.Sprintf("Error parsing Taskfile: %v", err)
+	}
 }
 
 // findTaskCommand checks if 'task' is available, falls back to 'go tool task',
-// and returns the appropriate command or an error if neither is found
+// and returns the appropriate command or an error if neither is found.
+// The detection only runs once per process; later calls return the cached result.
 func findTaskCommand() (TaskCommand, error) {
+	taskCmdOnce.Do(func() {
+		taskCmdResult, taskCmdErr = detectTaskCommand()
+	})
+	return taskCmdResult, taskCmdErr
+}
+
+// detectTaskCommand probes for the task binary
+func detectTaskCommand() (TaskCommand, error) {
 	// Check if 'task' is in PATH
 	if _, err := exec.LookPath("task"); err == nil {
 		return TaskCommand{Cmd: "task", Args: []string{}}, nil
@@ -134,291 +787,1322 @@ func findTaskCommand() (TaskCommand, error) {
 	}
 
 	// Neither is available
-	return TaskCommand{}, fmt.Errorf("task command not found in PATH")
+	return TaskCommand{}, ErrTaskNotFound
 }
 
-// parseTaskfile reads the Taskfile.yml and extracts tasks
-func parseTaskfile() ([]Task, error) {
-	// Look for Taskfile.yml or Taskfile.yaml in the current directory
-	var taskfilePath string
-	for _, name := range []string{"Taskfile.yml", "Taskfile.yaml"} {
-		if _, err := os.Stat(name); err == nil {
-			taskfilePath = name
-			break
+// taskfileNames are the Taskfile names task looks for, in order
+var taskfileNames = []string{
+	"Taskfile.yml", "taskfile.yml", "Taskfile.yaml", "taskfile.yaml",
+	"Taskfile.dist.yml", "taskfile.dist.yml", "Taskfile.dist.yaml", "taskfile.dist.yaml",
+}
+
+// plainTaskfileNames are extensionless Taskfile names task doesn't look for
+// itself. gt finds them after taskfileNames and passes them to task with
+// --taskfile.
+var plainTaskfileNames = []string{"Taskfile", "taskfile"}
+
+// searchedTaskfileNames are all the names gt looks for, in order
+var searchedTaskfileNames = slices.Concat(taskfileNames, plainTaskfileNames)
+
+// findTaskfile returns the path of the Taskfile in the current directory or
+// the nearest parent directory that has one, up to the repository root
+func findTaskfile() (string, error) {
+	// Look for a Taskfile in the current directory, then in parent directories
+	dir, err := os.Getwd()
+	if err != nil {
+		return "", err
+	}
+
+	for {
+		for _, name := range searchedTaskfileNames {
+			path := filepath.Join(dir, name)
+			if _, err := os.Stat(path); err == nil {
+				// Report where a symlinked Taskfile really lives
+				if real, err := filepath.EvalSymlinks(path); err == nil {
+					path = real
+				}
+				return path, nil
+			}
+			if _, err := os.Lstat(path); err == nil {
+				return "", fmt.Errorf("%s is a symlink to a file that doesn't exist", path)
+			}
+		}
+
+		// Don't pick up a stray Taskfile above the repository, e.g. in $HOME
+		if config.StopAtRepoRoot && isRepoRoot(dir) {
+			debugf("stopped looking for a Taskfile at the repository root %s", dir)
+			return "", ErrNoTaskfile
 		}
+
+		// Stop at the root, which is its own parent: / or a volume like C:\
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", ErrNoTaskfile
+		}
+
+		// Move to parent directory
+		dir = parent
+	}
+}
+
+// isRepoRoot reports whether dir is the root of a git repository. .git is
+// a file rather than a directory in worktrees and submodules.
+func isRepoRoot(dir string) bool {
+	_, err := os.Stat(filepath.Join(dir, ".git"))
+	return err == nil
+}
+
+// isPlainTaskfile reports whether path is an extensionless Taskfile, which
+// task only runs when it is given with --taskfile
+func isPlainTaskfile(path string) bool {
+	return slices.Contains(plainTaskfileNames, filepath.Base(path))
+}
+
+// parseTaskfile finds the Taskfile and extracts its tasks, returning the
+// path of the Taskfile too
+func parseTaskfile() ([]Task, string, error) {
+	start := time.Now()
+	taskfilePath, err := findTaskfile()
+	profiled("discovery", start)
+	if err != nil {
+		return nil, "", err
+	}
+	debugf("Taskfile: %s", taskfilePath)
+
+	// Run the tasks of an extensionless Taskfile against it, as if it was
+	// given with --taskfile
+	var found []Task
+	if isPlainTaskfile(taskfilePath) {
+		found, err = parseTaskfiles([]string{taskfilePath}, false)
+	} else {
+		found, err = parseTaskfileAt(taskfilePath)
 	}
+	return found, taskfilePath, err
+}
 
-	if taskfilePath == "" {
-		// Look for Taskfile.yml or Taskfile.yaml in parent directories
-		dir, err := os.Getwd()
+// parseTaskfiles merges the tasks of several Taskfiles into one list, with
+// each task set up to run against the Taskfile it came from. Tasks from
+// later files replace earlier ones with the same name, unless keepDuplicates
+// is set, in which case both are kept.
+func parseTaskfiles(paths []string, keepDuplicates bool) ([]Task, error) {
+	var merged []Task
+	index := map[string]int{}
+	for _, path := range paths {
+		fileTasks, err := parseTaskfileAt(path)
+		if err != nil && globalSearch {
+			// One broken project shouldn't hide the tasks of all the others
+			debugf("global search: skipping %s: %v", path, err)
+			continue
+		}
 		if err != nil {
 			return nil, err
 		}
 
-		for {
-			for _, name := range []string{"Taskfile.yml", "Taskfile.yaml"} {
-				path := filepath.Join(dir, name)
-				if _, err := os.Stat(path); err == nil {
-					taskfilePath = path
-					break
-				}
+		for _, task := range fileTasks {
+			task.Taskfile = path
+			if i, ok := index[task.Name]; ok && !keepDuplicates {
+				merged[i] = task
+				continue
 			}
+			index[task.Name] = len(merged)
+			merged = append(merged, task)
+		}
+	}
+	return merged, nil
+}
 
-			if taskfilePath != "" || dir == "/" {
-				break
-			}
+// parseTaskfileAt reads the Taskfile at taskfilePath and extracts its tasks,
+// along with the tasks of the Taskfiles it includes
+func parseTaskfileAt(taskfilePath string) ([]Task, error) {
+	tasks, err := parseTaskfileTree(taskfilePath, nil)
+	if err != nil {
+		return nil, err
+	}
 
-			// Move to parent directory
-			dir = filepath.Dir(dir)
-		}
+	// Task directories are relative to the Taskfile task is started with
+	root, err := filepath.Abs(filepath.Dir(taskfilePath))
+	if err != nil {
+		return nil, err
 	}
+	for i := range tasks {
+		tasks[i].WorkDir = resolveWorkDir(root, tasks[i].WorkDir)
+	}
+	return tasks, nil
+}
 
-	if taskfilePath == "" {
-		return nil, fmt.Errorf("no Taskfile.yml or Taskfile.yaml found")
+// parseTaskfileTree parses the Taskfile and its includes. ancestors holds
+// the Taskfiles that include this one, directly or not, to detect include
+// cycles; it is never modified, so branches can be parsed concurrently.
+func parseTaskfileTree(taskfilePath string, ancestors map[string]bool) ([]Task, error) {
+	source, err := filepath.Abs(taskfilePath)
+	if err != nil {
+		return nil, err
 	}
 
-	data, err := os.ReadFile(taskfilePath)
+	// Bound how many Taskfiles are read and parsed at once. The slot is
+	// released before the includes are parsed, so they can't deadlock
+	// waiting for the slots held by their parents.
+	parseSlots <- struct{}{}
+	tasks, taskfile, err := parseTaskfileTasks(taskfilePath, source)
+	<-parseSlots
 	if err != nil {
 		return nil, err
 	}
+	debugf("parsed %s: %d tasks", source, len(tasks))
 
-	// Parse YAML
-	var taskfile map[string]interface{}
-	if err := yaml.Unmarshal(data, &taskfile); err != nil {
+	// Add the tasks of included Taskfiles
+	parents := map[string]bool{source: true}
+	maps.Copy(parents, ancestors)
+	included, err := includedTasks(source, taskfile, parents)
+	if err != nil {
 		return nil, err
 	}
 
+	return append(tasks, included...), nil
+}
+
+// parseTaskfileTasks reads the tasks defined in the Taskfile itself, whose
+// absolute path is source, returning the decoded Taskfile too
+func parseTaskfileTasks(taskfilePath, source string) ([]Task, map[string]interface{}, error) {
+	defer profiled("read and parse", time.Now())
+	data, err := os.ReadFile(taskfilePath)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Parse YAML, keeping the node tree to know where tasks are defined
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, nil, newTaskfileError(taskfilePath, data, err)
+	}
+	var taskfile map[string]interface{}
+	if err := doc.Decode(&taskfile); err != nil {
+		return nil, nil, newTaskfileError(taskfilePath, data, err)
+	}
+	lines := taskLines(&doc)
+
+	// The top-level silent and method settings apply unless a task
+	// overrides them
+	silentDefault, _ := taskfile["silent"].(bool)
+	methodDefault, _ := taskfile["method"].(string)
+
 	// Extract tasks
 	tasks := []Task{}
 	if tasksMap, ok := taskfile["tasks"].(map[string]interface{}); ok {
 		for name, details := range tasksMap {
 			description := ""
 			var commands []string
+			var environment map[string]string
+			var sources, generates, deps, aliases []string
+			var dir, summary string
+			silent := silentDefault
+			method := methodDefault
 
 			if taskDetails, ok := details.(map[string]interface{}); ok {
-				// Get description
-				if desc, ok := taskDetails["desc"].(string); ok {
-					description = desc
-				} else if desc, ok := taskDetails["summary"].(string); ok {
-					description = desc
+				// Internal tasks can't be run directly, so aren't listed
+				if internal, _ := taskDetails["internal"].(bool); internal {
+					continue
+				}
+
+				// Get description, from the field the config prefers
+				desc, _ := taskDetails["desc"].(string)
+				summary, _ = taskDetails["summary"].(string)
+				description = preferredDesc(desc, summary)
+
+				// Get silent override
+				if value, ok := taskDetails["silent"].(bool); ok {
+					silent = value
+				}
+
+				// Get environment overrides
+				if env, ok := taskDetails["env"].(map[string]interface{}); ok {
+					environment = parseEnv(env)
 				}
 
 				// Get commands
 				if cmds, ok := taskDetails["cmds"].([]interface{}); ok {
-					for _, cmd := range cmds {
-						if cmdStr, ok := cmd.(string); ok {
-							commands = append(commands, cmdStr)
-						}
-					}
+					commands = parseCommands(cmds)
+				}
+
+				// Get up-to-date checking settings
+				if value, ok := taskDetails["method"].(string); ok {
+					method = value
+				}
+				if list, ok := taskDetails["sources"].([]interface{}); ok {
+					sources = parseCommands(list)
+				}
+				if list, ok := taskDetails["generates"].([]interface{}); ok {
+					generates = parseCommands(list)
+				}
+
+				// Get dependencies
+				if list, ok := taskDetails["deps"].([]interface{}); ok {
+					deps = parseDeps(list)
+				}
+
+				// Get the other names the task can be run by
+				if list, ok := taskDetails["aliases"].([]interface{}); ok {
+					aliases = parseCommands(list)
 				}
+
+				// Get the directory the task runs in
+				dir, _ = taskDetails["dir"].(string)
+			} else if cmd, ok := details.(string); ok {
+				// Shorthand form: the task is a single command
+				commands = []string{cmd}
+			} else if cmds, ok := details.([]interface{}); ok {
+				// Shorthand form: the task is a list of commands
+				commands = parseCommands(cmds)
 			}
 
-			tasks = append(tasks, Task{Name: name, Desc: description, Cmds: commands})
+			tasks = append(tasks, Task{
+				Name:      name,
+				Desc:      description,
+				Tags:      descTags(description),
+				Cmds:      commands,
+				Env:       environment,
+				Source:    source,
+				Line:      lines[name],
+				Silent:    silent,
+				Method:    method,
+				Sources:   sources,
+				Generates: generates,
+				Deps:      deps,
+				Aliases:   aliases,
+				Dir:       dir,
+				WorkDir:   dir,
+				Summary:   summary,
+			})
 		}
 	}
 
-	return tasks, nil
+	return tasks, taskfile, nil
 }
 
-// fuzzyFilter filters the list items based on the input
-func fuzzyFilter(items []list.Item, filter string) []list.Item {
-	if filter == "" {
-		return items
+// taskLines maps task names to the line they are defined on
+func taskLines(doc *yaml.Node) map[string]int {
+	lines := map[string]int{}
+	if node := tasksNode(doc); node != nil {
+		for j := 0; j+1 < len(node.Content); j += 2 {
+			lines[node.Content[j].Value] = node.Content[j].Line
+		}
 	}
+	return lines
+}
 
-	// Extract the string values to match against
-	var targets []string
-	for _, item := range items {
-		targets = append(targets, item.FilterValue())
+// tasksNode returns the mapping node of the Taskfile's tasks section, or nil
+// if it has none
+func tasksNode(doc *yaml.Node) *yaml.Node {
+	if doc.Kind != yaml.DocumentNode || len(doc.Content) == 0 {
+		return nil
 	}
 
-	// Perform fuzzy matching
-	matches := fuzzy.Find(filter, targets)
-
-	// Create a new slice with the matching items in order
-	var filtered []list.Item
-	for _, match := range matches {
-		filtered = append(filtered, items[match.Index])
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return nil
 	}
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		if root.Content[i].Value == "tasks" && root.Content[i+1].Kind == yaml.MappingNode {
+			return root.Content[i+1]
+		}
+	}
+	return nil
+}
 
-	return filtered
+// parseCommands extracts the commands from a task's cmds list, both plain
+// strings and map-form entries
+func parseCommands(cmds []interface{}) []string {
+	var commands []string
+	for _, cmd := range cmds {
+		switch cmd := cmd.(type) {
+		case string:
+			commands = append(commands, cmd)
+		case map[string]interface{}:
+			if command, ok := mapCommand(cmd); ok {
+				commands = append(commands, command)
+			}
+		}
+	}
+	return commands
 }
 
-// launchTUI starts the Bubble Tea TUI
-func launchTUI() {
-	// Convert tasks to list items
-	var items []list.Item
-	for _, task := range tasks {
-		items = append(items, task)
+// mapCommand renders a map-form command: a cmd entry as its command, and a
+// call to another task as "→ name (VAR=value, ...)"
+func mapCommand(cmd map[string]interface{}) (string, bool) {
+	if command, ok := cmd["cmd"].(string); ok {
+		return command, true
+	}
+	name, ok := cmd["task"].(string)
+	if !ok {
+		return "", false
 	}
 
-	// Create filter input
-	ti := textinput.New()
-	ti.Placeholder = "Filter tasks..."
-	ti.Focus()
-	ti.CharLimit = 50
-	ti.Width = 30
+	vars, _ := cmd["vars"].(map[string]interface{})
+	if len(vars) == 0 {
+		return "→ " + name, true
+	}
+	var assignments []string
+	for key, value := range parseEnv(vars) {
+		assignments = append(assignments, key+"="+value)
+	}
+	sort.Strings(assignments)
+	return "→ " + name + " (" + strings.Join(assignments, ", ") + ")", true
+}
 
-	// Create list
-	delegate := list.NewDefaultDelegate()
-	delegate.Styles.SelectedTitle = delegate.Styles.SelectedTitle.Foreground(lipgloss.Color("170"))
-	delegate.Styles.SelectedDesc = delegate.Styles.SelectedDesc.Foreground(lipgloss.Color("240"))
+// parseEnv converts a task's env map into displayable values. Static values
+// are kept as-is, while dynamic `sh:` values are shown as shell expressions.
+func parseEnv(env map[string]interface{}) map[string]string {
+	result := make(map[string]string, len(env))
+	for key, value := range env {
+		switch v := value.(type) {
+		case map[string]interface{}:
+			if sh, ok := v["sh"].(string); ok {
+				result[key] = "$(" + sh + ")"
+			}
+		case nil:
+			result[key] = ""
+		default:
+			result[key] = fmt.Sprint(v)
+		}
+	}
+	return result
+}
 
-	// Reduce spacing between items to absolute minimum
-	delegate.SetSpacing(0) // Minimal spacing between items
-	delegate.Styles.NormalTitle = delegate.Styles.NormalTitle.PaddingTop(0).PaddingBottom(0).MarginTop(0).MarginBottom(0)
-	delegate.Styles.NormalDesc = delegate.Styles.NormalDesc.PaddingTop(0).PaddingBottom(0).MarginTop(0).MarginBottom(0)
-	delegate.Styles.SelectedTitle = delegate.Styles.SelectedTitle.PaddingTop(0).PaddingBottom(0).MarginTop(0).MarginBottom(0)
-	delegate.Styles.SelectedDesc = delegate.Styles.SelectedDesc.PaddingTop(0).PaddingBottom(0).MarginTop(0).MarginBottom(0)
+// documentedTasks returns only the tasks that have a description
+func documentedTasks(tasks []Task) []Task {
+	var documented []Task
+	for _, task := range tasks {
+		if task.Desc != "" {
+			documented = append(documented, task)
+		}
+	}
+	return documented
+}
 
-	l := list.New(items, delegate, 0, 0)
-	l.SetShowTitle(false) // Remove the title completely
-	l.SetShowStatusBar(false)
-	l.SetFilteringEnabled(false) // We'll handle filtering ourselves
+// printTaskList writes the tasks as an aligned name/description listing
+func printTaskList(w io.Writer, tasks []Task, all bool) {
+	if !all {
+		tasks = documentedTasks(tasks)
+	}
 
-	// Create initial model
-	m := model{
-		list:         l,
-		filter:       ti,
-		filteredList: items,
-		allItems:     items,
-		expanded:     false, // Start with details hidden
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, task := range tasks {
+		fmt.Fprintf(tw, "* %s:\t%s\n", task.Name, task.Desc)
 	}
+	tw.Flush()
+}
 
-	// We won't actually use the filter's focus state anymore
-	// but we'll set this to simplify the code
-	m.filter.Focus()
+// groupKey returns the group a task is listed under for the given mode
+func groupKey(task Task, mode groupMode) string {
+	switch mode {
+	case groupByNamespace:
+		return taskNamespace(task.Name)
+	case groupByFile:
+		return task.Source
+	}
+	return ""
+}
 
-	// Run the TUI
-	p := tea.NewProgram(m, tea.WithAltScreen())
-	if _, err := p.Run(); err != nil {
-		fmt.Printf("Error running program: %v\n", err)
-		os.Exit(1)
+// groupItems reorders items so that tasks sharing a group are adjacent,
+// keeping the existing order within each group
+func groupItems(items []list.Item, mode groupMode) []list.Item {
+	if mode == groupFlat {
+		return items
 	}
+
+	grouped := make([]list.Item, len(items))
+	copy(grouped, items)
+	sort.SliceStable(grouped, func(i, j int) bool {
+		return groupKey(grouped[i].(Task), mode) < groupKey(grouped[j].(Task), mode)
+	})
+
+	return grouped
 }
 
-// Init initializes the TUI model
-func (m model) Init() tea.Cmd {
-	return textinput.Blink
+// groupHeader returns the display text for a group key
+func groupHeader(key string, mode groupMode) string {
+	if mode == groupByNamespace && key == "" {
+		return "(root)"
+	}
+	if mode == groupByFile {
+		if wd, err := os.Getwd(); err == nil {
+			if rel, err := filepath.Rel(wd, key); err == nil {
+				return rel
+			}
+		}
+	}
+	return key
 }
 
-// Update handles TUI events
-func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
-	var cmds []tea.Cmd
+// launchTUI starts the Bubble Tea TUI with an optional initial filter, or
+// the simple picker when the TUI can't be used. It returns the exit code,
+// leaving it to the caller to exit with it.
+func launchTUI(filter string) int {
+	// Fall back to a numbered list where the TUI can't be drawn
+	if useSimplePicker() {
+		return runSimplePicker(filter)
+	}
 
-	switch msg := msg.(type) {
+	m := newModel()
+	if viewName != "" {
+		view, err := findView(viewName)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return exitError
+		}
+		m.applyView(view)
+	}
+	if filter != "" {
+		m.filter.SetValue(filter)
+		m.applyFilter()
+	}
+
+	// Run the TUI, keeping stdout clean for the selection if it is printed
+	opts := []tea.ProgramOption{tea.WithAltScreen()}
+	if printSelection {
+		opts = append(opts, tea.WithOutput(os.Stderr))
+		// Detect colors from the terminal we draw on, not the captured stdout
+		lipgloss.SetDefaultRenderer(lipgloss.NewRenderer(os.Stderr))
+	}
+	// Keep the logs and events away from the screen until the TUI is done
+	var logs, events bytes.Buffer
+	startDebugLog(&logs)
+	eventLog = &events
+	final, err := tea.NewProgram(m, opts...).Run()
+	startDebugLog(os.Stderr)
+	eventLog = os.Stderr
+	os.Stderr.Write(logs.Bytes())
+	os.Stderr.Write(events.Bytes())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error running program: %v\n", err)
+		return exitError
+	}
+	if err := final.(model).err; err != nil {
+		return initExitCode(err)
+	}
+
+	if printSelection {
+		picked := final.(model).picked
+		if len(picked) == 0 {
+			return exitNoSelection
+		}
+		for _, name := range picked {
+			fmt.Println(name)
+		}
+	}
+	return 0
+}
+
+// printSelection prints the chosen task names instead of running them
+var printSelection bool
+
+// pick quits the TUI, remembering the tasks to print for --print-selection
+func (m model) pick(tasks ...Task) (tea.Model, tea.Cmd) {
+	for _, task := range tasks {
+		selectRun(task.Name)
+		m.picked = append(m.picked, task.Name)
+	}
+	return m, tea.Quit
+}
+
+// newModel creates the initial TUI model for the loaded tasks
+func newModel() model {
+
+	// Create filter input
+	ti := textinput.New()
+	ti.Placeholder = "Filter tasks..."
+	ti.Focus()
+	ti.CharLimit = 50
+	ti.Width = 30 // Resized to fit the terminal on WindowSizeMsg
+
+	// Pick the configured theme, falling back to the first preset
+	themeIndex, ok := findTheme(config.Theme)
+	unknownTheme := config.Theme != "" && !ok
+
+	// The density was checked when the config was loaded
+	listDensity, _ := parseDensity(config.Density)
+
+	// Create list, filled in once the tasks are loaded
+	l := list.New(nil, newDelegate(themes[themeIndex], false, listDensity), 0, 0)
+	l.SetShowTitle(false) // Remove the title completely
+	l.SetShowStatusBar(false)
+	l.SetShowPagination(false)
+	l.SetShowHelp(false)
+	l.SetFilteringEnabled(false) // We'll handle filtering ourselves
+
+	// Create initial model
+	m := model{
+		list:        l,
+		filter:      ti,
+		expanded:    false, // Start with details hidden
+		hyperlinks:  hyperlinksSupported(),
+		theme:       themeIndex,
+		density:     listDensity,
+		loading:     true,
+		yes:         assumeYes,
+		verbose:     verbose,
+		matchCase:   caseSensitive,
+		concurrency: concurrency,
+		spinner:     spinner.New(spinner.WithSpinner(spinner.Dot)),
+	}
+	if unknownTheme {
+		m.status = "unknown theme " + config.Theme + ", using " + themes[themeIndex].name
+	}
+
+	// We won't actually use the filter's focus state anymore
+	// but we'll set this to simplify the code
+	m.filter.Focus()
+
+	return m
+}
+
+// Init initializes the TUI model
+func (m model) Init() tea.Cmd {
+	if m.loading {
+		return tea.Batch(textinput.Blink, m.spinner.Tick, loadTasks)
+	}
+	return textinput.Blink
+}
+
+// Update handles TUI events
+func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmds []tea.Cmd
+
+	switch msg := msg.(type) {
+	case tasksLoadedMsg:
+		return m.tasksLoaded(msg)
+	case spinner.TickMsg:
+		if !m.loading {
+			return m, nil
+		}
+		var cmd tea.Cmd
+		m.spinner, cmd = m.spinner.Update(msg)
+		return m, cmd
 	case tea.KeyMsg:
-		// First check if filter is focused
-		if m.filter.Focused() {
-			switch msg.String() {
-			case "ctrl+c":
-				return m, tea.Quit
-			case "esc":
-				// Blur the filter on ESC to enter navigation mode
-				m.filter.Blur()
-				return m, nil
-			case "tab":
-				// Toggle expanded state
-				m.expanded = !m.expanded
-				return m, nil
-			case "enter":
-				if len(m.filteredList) > 0 {
-					i := m.list.SelectedItem()
-					task, ok := i.(Task)
-					if ok {
-						m.selected = true
-						// Run the selected task and quit when done
-						return m, tea.Sequence(
-							tea.ExecProcess(
-								exec.Command(taskCmd.Cmd, append(taskCmd.Args, task.Name)...),
-								func(err error) tea.Msg {
-									return nil
-								},
-							),
-							tea.Quit,
-						)
-					}
+		// Loading failed, so there is nothing to do but quit
+		if m.err != nil {
+			return m, tea.Quit
+		}
+	}
+
+	// Captured task output takes over the screen while it is shown
+	if m.run != nil {
+		if _, ok := msg.(tea.WindowSizeMsg); !ok {
+			return m.updateRun(msg)
+		}
+	}
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		// The keymap preset's keys act like the arrow keys they map to
+		msg = activeKeymap.translate(msg, m.copying != nil || m.scratching || m.savingView || m.filter.Focused())
+
+		// The name of a copied task is being entered
+		if m.copying != nil {
+			return m.updateCopy(msg)
+		}
+
+		// An ad-hoc command is being entered
+		if m.scratching {
+			return m.updateScratch(msg)
+		}
+
+		// The name of a view to save is being entered
+		if m.savingView {
+			return m.updateSaveView(msg)
+		}
+
+		// The Taskfile picker takes the keys while it is shown
+		if m.switching != nil {
+			return m.updateSwitch(msg)
+		}
+
+		// Quitting with marked tasks waits for confirmation
+		if m.confirmQuit {
+			return m.updateConfirmQuit(msg)
+		}
+
+		// Pasted text always goes to the filter in one piece
+		if msg.Paste {
+			return m.pasteFilter(msg)
+		}
+
+		// With line numbers, typing a number in navigation mode jumps to it
+		if m.jumpKey(msg.String()) {
+			return m, nil
+		}
+
+		// The dependencies in the details view can be chosen and run
+		next, cmd, handled := m.updateDeps(msg.String())
+		if m = next; handled {
+			return m, cmd
+		}
+
+		// Number keys launch quick menu tasks in navigation mode
+		if task, ok := m.menuTask(msg.String()); ok {
+			return m.execTask(task)
+		}
+
+		// Arrow keys move across the grid when it is shown
+		if m.gridKey(msg.String(), !m.filter.Focused()) {
+			return m, nil
+		}
+
+		// Keys that type text go to the filter while it is focused; see
+		// keys for the bindings
+		typing := m.filter.Focused()
+		switch {
+		case m.matches(msg, keys.ForceQuit):
+			return m, tea.Quit
+		case typing && m.matches(msg, keys.Blur):
+			// Blur the filter on ESC to enter navigation mode
+			m.filter.Blur()
+			return m, nil
+		case !typing && m.matches(msg, keys.Quit):
+			return m.quit()
+		case m.matches(msg, keys.Details):
+			// Toggle expanded state
+			m.expanded = !m.expanded
+			return m, m.askTaskInfo()
+		case m.matches(msg, keys.AllDescs):
+			// Toggle showing all descriptions below the task names
+			m.allDescs = !m.allDescs
+			m.list.SetDelegate(newDelegate(m.currentTheme(), m.allDescs, m.density))
+			return m, nil
+		case m.matches(msg, keys.Group):
+			// Cycle grouping mode
+			m.grouping = m.grouping.next()
+			m.applyFilter()
+			return m, nil
+		case m.matches(msg, keys.Grid):
+			// Toggle between a single column and a grid of task names
+			m.grid = !m.grid
+			return m, nil
+		case m.matches(msg, keys.Theme):
+			// Cycle through the theme presets
+			m.cycleTheme()
+			return m, nil
+		case m.matches(msg, keys.RunHere):
+			// Run the selected task with its output shown in the TUI
+			return m.runCaptured()
+		case m.matches(msg, keys.Reload):
+			// Parse the Taskfile again, marking the tasks changed since the start
+			return m.reload()
+		case m.matches(msg, keys.Case):
+			// Toggle case-sensitive filtering
+			m.matchCase = !m.matchCase
+			m.applyFilter()
+			return m, nil
+		case m.matches(msg, keys.Select):
+			// Run the selected task and quit when done
+			return m.execSelected()
+		case m.matches(msg, keys.Down):
+			// Down navigation
+			var listCmd tea.Cmd
+			m.list, listCmd = m.list.Update(tea.KeyMsg{Type: tea.KeyDown})
+			cmds = append(cmds, listCmd)
+		case m.matches(msg, keys.Up):
+			// Up navigation
+			var listCmd tea.Cmd
+			m.list, listCmd = m.list.Update(tea.KeyMsg{Type: tea.KeyUp})
+			cmds = append(cmds, listCmd)
+		case typing:
+			// All other keys go to filter input
+			var filterCmd tea.Cmd
+			m.filter, filterCmd = m.filter.Update(msg)
+			cmds = append(cmds, filterCmd)
+
+			// Filter the list based on input
+			m.applyFilter()
+			cmds = append(cmds, m.scheduleAutoRun())
+
+		// The rest only apply in navigation mode (filter not focused)
+		case m.matches(msg, keys.Background):
+			// Run the selected task in the background, staying in the list
+			return m.runBackground()
+		case m.matches(msg, keys.TaskList):
+			// Show task's own listing for comparison
+			return m.showTaskList()
+		case m.matches(msg, keys.Retry) && m.failed != nil:
+			// Retry the last task if it failed
+			return m.execTask(*m.failed)
+		case m.matches(msg, keys.Stale):
+			// Toggle showing only stale tasks, checking their status on first use
+			m.staleOnly = !m.staleOnly
+			if m.staleOnly && m.upToDate == nil && !m.checking {
+				m.checking = true
+				var all []Task
+				for _, item := range m.allItems {
+					all = append(all, item.(Task))
 				}
-			case "down", "up":
-				// Pass navigation keys to the list
-				var listCmd tea.Cmd
-				m.list, listCmd = m.list.Update(msg)
-				cmds = append(cmds, listCmd)
-			default:
-				// All other keys go to filter input
-				var filterCmd tea.Cmd
-				m.filter, filterCmd = m.filter.Update(msg)
-				cmds = append(cmds, filterCmd)
-
-				// Filter the list based on input
-				m.filteredList = fuzzyFilter(m.allItems, m.filter.Value())
-				m.list.SetItems(m.filteredList)
+				return m, checkTaskStatus(all)
 			}
-		} else {
-			// Navigation mode (filter not focused)
-			switch msg.String() {
-			case "ctrl+c", "esc", "q":
-				return m, tea.Quit
-			case "tab":
-				// Toggle expanded state
-				m.expanded = !m.expanded
-				return m, nil
-			case "enter":
-				if len(m.filteredList) > 0 {
-					i := m.list.SelectedItem()
-					task, ok := i.(Task)
-					if ok {
-						m.selected = true
-						// Run the selected task and quit when done
-						return m, tea.Sequence(
-							tea.ExecProcess(
-								exec.Command(taskCmd.Cmd, append(taskCmd.Args, task.Name)...),
-								func(err error) tea.Msg {
-									return nil
-								},
-							),
-							tea.Quit,
-						)
-					}
-				}
-			case "down", "j":
-				// Down navigation
-				var listCmd tea.Cmd
-				m.list, listCmd = m.list.Update(tea.KeyMsg{Type: tea.KeyDown})
-				cmds = append(cmds, listCmd)
-			case "up", "k":
-				// Up navigation
-				var listCmd tea.Cmd
-				m.list, listCmd = m.list.Update(tea.KeyMsg{Type: tea.KeyUp})
-				cmds = append(cmds, listCmd)
-			case "/":
-				// Focus the filter input
-				m.filter.Focus()
-				return m, textinput.Blink
-			default:
-				// Any other character starts filter and adds it
-				m.filter.Focus()
-				m.filter.SetValue(msg.String())
-				m.filteredList = fuzzyFilter(m.allItems, m.filter.Value())
-				m.list.SetItems(m.filteredList)
-				return m, textinput.Blink
+			m.applyFilter()
+			return m, nil
+		case m.matches(msg, keys.Yes):
+			// Toggle confirming task prompts automatically
+			m.yes = !m.yes
+			m.status = "auto-confirm prompts: off"
+			if m.yes {
+				m.status = "auto-confirm prompts: on"
 			}
+			return m, nil
+		case m.matches(msg, keys.Verbose):
+			// Run the selected task with task's verbose logging
+			return m.execVerbose()
+		case m.matches(msg, keys.Docs):
+			// Open the selected task's documentation link
+			m.openDocs()
+			return m, nil
+		case m.matches(msg, keys.Copy):
+			// Copy the selected task's definition under a new name
+			return m.startCopy()
+		case m.matches(msg, keys.Scratch):
+			// Run an ad-hoc command in the Taskfile's directory
+			return m.startScratch()
+		case m.matches(msg, keys.Switch):
+			// Switch to another Taskfile of the project
+			m.status = "looking for Taskfiles..."
+			return m, scanTaskfiles
+		case m.matches(msg, keys.Pin):
+			// Pin the selected task's details, or unpin them
+			m.togglePin()
+			return m, m.askTaskInfo()
+		case m.matches(msg, keys.HideDone):
+			// Toggle hiding the tasks already run this session
+			m.hideRan = !m.hideRan
+			m.applyFilter()
+			return m, nil
+		case m.matches(msg, keys.Mark):
+			// Mark the selected task to run together with others
+			m.toggleMark()
+			return m, nil
+		case m.matches(msg, keys.Namespace):
+			// Run every task in the selected task's namespace
+			return m.execNamespace()
+		case m.matches(msg, keys.Source):
+			// Swap between the parsed tasks and the ones task lists
+			return m.toggleSource()
+		case m.matches(msg, keys.More):
+			// Allow task to run more tasks in parallel
+			m.stepConcurrency(1)
+			return m, nil
+		case m.matches(msg, keys.Fewer):
+			// Allow task to run fewer tasks in parallel
+			m.stepConcurrency(-1)
+			return m, nil
+		case m.matches(msg, keys.Density):
+			// Cycle how much room each task takes in the list
+			m.cycleDensity()
+			return m, nil
+		case m.matches(msg, keys.DepsLine):
+			// Toggle the selected task's deps on a line below it
+			m.depChips = !m.depChips
+			return m, nil
+		case m.matches(msg, keys.Fold) && m.foldable():
+			// Show all of a long command list, or fold it again
+			m.toggleFold()
+			return m, m.askTaskInfo()
+		case m.matches(msg, keys.SaveView):
+			// Save the filter and toggles as a named view
+			return m.startSaveView()
+		case m.matches(msg, keys.Documented):
+			// Toggle between all tasks and documented tasks only
+			m.documented = !m.documented
+			m.applyFilter()
+			return m, nil
+		case m.matches(msg, keys.Filter):
+			// Focus the filter input
+			m.filter.Focus()
+			return m, textinput.Blink
+		default:
+			// Any other character starts filter and adds it, including r
+			// and f when there is nothing to retry or fold
+			m.filter.Focus()
+			m.filter.SetValue(msg.String())
+			m.applyFilter()
+			return m, textinput.Blink
+		}
+
+	case execFinishedMsg:
+		// Back from a foreground run. Without keep_open only failed runs
+		// come back, in navigation mode so the retry key is available.
+		if m.selected && msg.err == nil {
+			return m, tea.Quit
 		}
+		m.selected = false
+		m.status = resultStatus(msg.task.Name, msg.err, msg.elapsed) + artifactsNote(msg.artifacts)
+		m.lastElapsed = msg.elapsed
+		m.markRan(msg.task)
+		m.failed = nil
+		if msg.err != nil {
+			m.failed = &msg.task
+			m.status += " • r: retry"
+		}
+		m.filter.Blur()
+
+	case batchFinishedMsg:
+		// Back from running the marked tasks with keep_open
+		m.status = batchSummary(msg.results, len(msg.tasks))
+		for i := range msg.results {
+			m.markRan(msg.tasks[i])
+		}
+		m.filter.Blur()
+
+	case taskfilesFoundMsg:
+		return m.taskfilesFound(msg)
+
+	case autoRunMsg:
+		return m.autoRunDue(msg)
+
+	case taskInfoMsg:
+		return m.taskInfoLoaded(msg)
+
+	case backgroundDoneMsg:
+		return m.backgroundDone(msg)
+
+	case backgroundTickMsg:
+		return m.backgroundTicked()
+
+	case taskfilesPolledMsg:
+		return m.taskfilesPolled(msg)
+
+	case taskStatusMsg:
+		m.checking = false
+		m.upToDate = msg
+		m.applyFilter()
 
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
-		m.list.SetSize(msg.Width, msg.Height-6) // Reserve space for filter and help text
+		m.list.SetSize(msg.Width, msg.Height-m.chromeHeight()) // Reserve space for filter and help text
+		m.filter.Width = filterWidth(msg.Width)
+		m.output.Width = msg.Width
+		m.output.Height = max(msg.Height-6, 1)
 	}
 
 	return m, tea.Batch(cmds...)
 }
 
+// taskID identifies a task among the loaded ones. With several --taskfile
+// and --keep-duplicates names repeat, so like marking it goes by the
+// Taskfile too.
+func taskID(task Task) string {
+	return task.Taskfile + "\x00" + task.Name
+}
+
+// markRan records that the task was run during this session
+func (m *model) markRan(task Task) {
+	if m.ran == nil {
+		m.ran = map[string]bool{}
+	}
+	m.ran[taskID(task)] = true
+	if m.hideRan {
+		m.applyFilter()
+	}
+}
+
+// pasteFilter inserts pasted text into the filter, focusing it first, and
+// filters once for the whole paste. Surrounding whitespace, such as a
+// trailing newline, is dropped since it would never match a task name.
+func (m model) pasteFilter(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	msg.Runes = []rune(strings.TrimSpace(string(msg.Runes)))
+	m.filter.Focus()
+
+	var cmd tea.Cmd
+	m.filter, cmd = m.filter.Update(msg)
+	m.applyFilter()
+	return m, tea.Batch(cmd, textinput.Blink, m.scheduleAutoRun())
+}
+
+// execSelected runs the marked tasks, or else the selected task, in the
+// foreground and quits when done
+func (m model) execSelected() (tea.Model, tea.Cmd) {
+	if len(m.marked) > 0 {
+		return m.execTasks(m.marked)
+	}
+	if len(m.filteredList) == 0 {
+		return m, nil
+	}
+	task, ok := m.list.SelectedItem().(Task)
+	if !ok {
+		return m, nil
+	}
+	return m.execTask(task)
+}
+
+// execVerbose runs the selected task in the foreground with --verbose
+func (m model) execVerbose() (tea.Model, tea.Cmd) {
+	task, ok := m.list.SelectedItem().(Task)
+	if !ok || len(m.filteredList) == 0 {
+		return m, nil
+	}
+
+	// The command is built right away, so verbose only applies to this run
+	previous := m.verbose
+	m.verbose = true
+	next, cmd := m.execTask(task)
+	if next, ok := next.(model); ok {
+		next.verbose = previous
+		return next, cmd
+	}
+	return next, cmd
+}
+
+// runOptions returns the flags to run tasks from the TUI with
+func (m model) runOptions() runOptions {
+	return runOptions{yes: m.yes, verbose: m.verbose, concurrency: m.concurrency}
+}
+
+// execTask runs the task in the foreground. gt quits when it succeeds,
+// unless keep_open is configured, and returns to the list to offer a
+// retry when it fails.
+func (m model) execTask(task Task) (tea.Model, tea.Cmd) {
+	if printSelection {
+		return m.pick(task)
+	}
+	if safeMode {
+		return m.refuseInSafeMode()
+	}
+
+	selectRun(task.Name)
+	startRun(task.Name, nil)
+	started := time.Now()
+	before := generatedFiles(task)
+	run := tea.ExecProcess(
+		taskCmd.command(task.Taskfile, taskRunArgs(m.runOptions(), task.Name)...),
+		func(err error) tea.Msg {
+			finishRun(task.Name, nil, err, time.Since(started))
+			msg := execFinishedMsg{task: task, err: err, elapsed: time.Since(started)}
+			if err == nil {
+				msg.artifacts = generatedSince(task, before)
+			}
+			return msg
+		},
+	)
+
+	m.selected = !config.KeepOpen
+	return m, run
+}
+
+// refuseInSafeMode explains why nothing happened when a task was chosen in
+// safe mode
+func (m model) refuseInSafeMode() (tea.Model, tea.Cmd) {
+	m.status = "execution disabled in safe mode"
+	return m, nil
+}
+
+// execFinishedMsg is sent when a task run in the foreground has exited
+type execFinishedMsg struct {
+	task      Task
+	err       error
+	elapsed   time.Duration
+	artifacts []string // Generated files created or updated by the run
+}
+
+// resolveMenu looks up the configured menu task names, returning the
+// matching tasks (at most 9) and the names that don't exist
+func resolveMenu(names []string, tasks []Task) ([]Task, []string) {
+	var menu []Task
+	var skipped []string
+	for _, name := range names {
+		found := false
+		for _, task := range tasks {
+			if task.Name == name {
+				menu = append(menu, task)
+				found = true
+				break
+			}
+		}
+		if !found {
+			skipped = append(skipped, name)
+		}
+	}
+	if len(menu) > 9 {
+		menu = menu[:9]
+	}
+	return menu, skipped
+}
+
+// menuFirst reorders items so the menu tasks come first, in menu order
+func menuFirst(items []list.Item, menu []Task) []list.Item {
+	ordered := make([]list.Item, 0, len(items))
+	for _, task := range menu {
+		ordered = append(ordered, task)
+	}
+	for _, item := range items {
+		isMenu := false
+		for _, task := range menu {
+			if item.(Task).Name == task.Name {
+				isMenu = true
+				break
+			}
+		}
+		if !isMenu {
+			ordered = append(ordered, item)
+		}
+	}
+	return ordered
+}
+
+// menuNumber returns the quick-launch number of the task, or 0 if it isn't
+// in the menu
+func (m model) menuNumber(name string) int {
+	for i, task := range m.menu {
+		if task.Name == name {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+// menuTask returns the menu task bound to key, which only applies in
+// navigation mode with the filter empty, so a filter can start with a number
+func (m model) menuTask(key string) (Task, bool) {
+	if m.filter.Focused() || m.filter.Value() != "" || len(key) != 1 || key[0] < '1' || key[0] > '9' {
+		return Task{}, false
+	}
+	n := int(key[0] - '0')
+	if n > len(m.menu) {
+		return Task{}, false
+	}
+	return m.menu[n-1], true
+}
+
+// applyFilter recomputes the visible items from the filter and grouping mode
+func (m *model) applyFilter() {
+	// Tasks already run stay reachable by typing a filter
+	hideRan := m.hideRan && m.filter.Value() == ""
+
+	items := m.allItems
+	if m.documented || (m.staleOnly && m.upToDate != nil) || hideRan {
+		items = nil
+		for _, item := range m.allItems {
+			task := item.(Task)
+			if m.documented && task.Desc == "" {
+				continue
+			}
+			if m.staleOnly && m.upToDate[taskID(task)] {
+				continue
+			}
+			if hideRan && m.ran[taskID(task)] {
+				continue
+			}
+			items = append(items, item)
+		}
+	}
+
+	var filtered []list.Item
+	filtered, m.totalMatches, m.filterErr = fuzzyFilter(items, m.filter.Value(), config.MaxResults, m.matchCase)
+	m.filteredList = groupItems(filtered, m.grouping)
+	m.list.SetItems(m.filteredList)
+}
+
+// parseDeps extracts the task names from a deps list, whose entries are
+// either a name or a map with a task key
+func parseDeps(deps []interface{}) []string {
+	var names []string
+	for _, dep := range deps {
+		switch dep := dep.(type) {
+		case string:
+			names = append(names, dep)
+		case map[string]interface{}:
+			if name, ok := dep["task"].(string); ok {
+				names = append(names, name)
+			}
+		}
+	}
+	return names
+}
+
+// foldCmds is how many commands are shown of a task with more than
+// foldThreshold, until they are unfolded
+const (
+	foldThreshold = 8
+	foldCmds      = 5
+)
+
+// preferredDesc returns the description listed for a task with the given
+// desc and summary fields: the one desc_field names, or else the other
+func preferredDesc(desc, summary string) string {
+	// Block scalars leave a trailing newline on summaries
+	summary = strings.TrimSpace(summary)
+	if config.DescField == "summary" {
+		desc, summary = summary, desc
+	}
+	if desc != "" {
+		return desc
+	}
+	return summary
+}
+
+// summaryDetails renders a summary in the details view, one line each
+func summaryDetails(summary string) string {
+	details := "\n    summary:"
+	for _, line := range strings.Split(strings.TrimSpace(summary), "\n") {
+		details += "\n      " + line
+	}
+	return details
+}
+
+// detailsView renders the expanded details shown below the selected task,
+// marking the dependency at index dep, if any. Long command lists are
+// folded unless unfold is set.
+func detailsView(task Task, dep int, unfold bool) string {
+	var details string
+	if task.Desc != "" {
+		details += "\n    desc: " + renderMarkdown(task.Desc)
+	} else {
+		details += "\n    desc: NO DESCRIPTION"
+	}
+	// The summary is shown from task's own report when there is one
+	if task.Summary != "" && strings.TrimSpace(task.Summary) != task.Desc && (task.Info == nil || task.Info.Summary == "") {
+		details += summaryDetails(task.Summary)
+	}
+	details += infoDetails(task)
+	details += workDirDetails(task)
+	if task.Silent {
+		details += "\n    silent: commands are not echoed"
+	}
+	if len(task.Env) > 0 {
+		details += "\n    env:"
+		keys := make([]string, 0, len(task.Env))
+		for key := range task.Env {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			details += "\n      " + key + "=" + task.Env[key]
+		}
+	}
+	if task.Method != "" {
+		details += "\n    method: " + task.Method
+	} else if len(task.Sources) > 0 {
+		details += "\n    method: checksum (default)"
+	}
+	if len(task.Sources) > 0 {
+		details += "\n    sources: " + strings.Join(task.Sources, ", ")
+	}
+	if len(task.Generates) > 0 {
+		details += "\n    generates: " + strings.Join(task.Generates, ", ")
+	}
+	if len(task.Deps) > 0 {
+		details += "\n    deps:"
+		for i, name := range task.Deps {
+			if i == dep {
+				details += "\n    > " + name
+			} else {
+				details += "\n      " + name
+			}
+		}
+	}
+	if len(task.Cmds) > 0 {
+		details += "\n    cmds:"
+		cmds := task.Cmds
+		if !unfold && len(cmds) > foldThreshold {
+			cmds = cmds[:foldCmds]
+		}
+		for _, cmd := range cmds {
+			details += "\n      " + cmd
+		}
+		if folded := len(task.Cmds) - len(cmds); folded > 0 {
+			details += fmt.Sprintf("\n      … +%d more (f: show all)", folded)
+		}
+	}
+	return details
+}
+
+// filterLabel precedes the filter text in the TUI
+const filterLabel = "Filter: "
+
+// filterWidth returns how much of the filter text fits on a terminal of the
+// given width, after the filter line's padding and label
+func filterWidth(width int) int {
+	return max(width-4-2-len(filterLabel), 1)
+}
+
+// scrollFilter shortens the filter text to width by keeping its end, where
+// typing happens, so long filters scroll rather than wrap
+func scrollFilter(value string, width int) string {
+	runes := []rune(value)
+	if len(runes) <= width {
+		return value
+	}
+	return "…" + string(runes[len(runes)-width+1:])
+}
+
+// emptyMessage explains why no tasks are listed and how to get them back
+func (m model) emptyMessage() string {
+	if query := m.filter.Value(); query != "" {
+		return fmt.Sprintf("no tasks match '%s' • backspace to edit the filter, or esc and type to start over", query)
+	}
+	return "no tasks to show • a, s and d toggle which tasks are hidden"
+}
+
+// taskStyle returns the style of a task's line, dimming tasks that were
+// already run this session and coloring others by the color rules
+func (m model) taskStyle(task Task, selected bool) lipgloss.Style {
+	switch {
+	case selected:
+		return m.currentTheme().selectedStyle()
+	case m.ran[taskID(task)]:
+		return m.currentTheme().mutedStyle()
+	}
+	if color, ok := taskColor(task.Name); ok {
+		return m.currentTheme().normalStyle().Foreground(color)
+	}
+	return m.currentTheme().normalStyle()
+}
+
+// taskLabel renders the task's name as shown in the list, with its menu
+// number and markers, along with its width on screen
+func (m model) taskLabel(task Task, index int) (string, int) {
+	name := displayName(task.Name)
+	line, width := name, lipgloss.Width(name)
+	if m.hyperlinks {
+		line = hyperlink(taskURL(task), name)
+	}
+
+	if n := m.menuNumber(task.Name); n > 0 {
+		prefix := fmt.Sprintf("[%d] ", n)
+		line, width = prefix+line, width+len(prefix)
+	}
+	if m.isMarked(task) {
+		line, width = "+ "+line, width+2
+	}
+	if number := m.lineNumber(index); number != "" {
+		line, width = number+line, width+len(number)
+	}
+	if len(taskfiles) > 1 {
+		tag := "(" + taskfileTag(task.Taskfile) + ")"
+		line, width = line+" "+m.currentTheme().mutedStyle().Render(tag), width+1+lipgloss.Width(tag)
+	}
+	if len(task.Tags) > 0 {
+		chips := "#" + strings.Join(task.Tags, " #")
+		line, width = line+" "+m.currentTheme().mutedStyle().Render(chips), width+1+lipgloss.Width(chips)
+	}
+	// Mark the tasks edited since the session started
+	if m.changed[task.Name] {
+		line, width = line+" ✎", width+2
+	}
+	// Mark stale tasks once status is known
+	if m.upToDate != nil && !m.upToDate[taskID(task)] {
+		line, width = line+" •", width+2
+	}
+	return line, width
+}
+
 // View renders the TUI
 func (m model) View() string {
 	if m.selected {
 		return "Running task..."
 	}
+	if m.run != nil {
+		return m.viewRun()
+	}
+	if m.err != nil {
+		return "\n" + initErrorMessage(m.err) + "\n\npress any key to quit"
+	}
 
 	// Create a clean filter without border
 	filterStyle := lipgloss.NewStyle().
@@ -430,66 +2114,313 @@ func (m model) View() string {
 	if m.filter.Value() == "" {
 		filterContent = "Type to filter tasks..."
 	} else {
-		filterContent = "Filter: " + m.filter.Value()
+		filterContent = filterLabel + scrollFilter(m.filter.Value(), m.filter.Width)
+		if m.filterErr != nil {
+			filterContent += "  (" + m.filterErr.Error() + ")"
+		}
+	}
+
+	if m.copying != nil {
+		filterContent = "Copy " + m.copying.Name + " as: " + m.copyName.View()
+	}
+	if m.scratching {
+		filterContent = "Run in " + taskfileDir() + ": " + m.scratchCmd.View()
+	}
+	if m.savingView {
+		filterContent = "Save view as: " + m.viewInput.View()
 	}
 
 	filterView := filterStyle.Render(filterContent)
 
-	// Create a custom ultra-compact list rendering
+	// Render the tasks in a grid or one per line
+	var listItems string
+	if m.loading {
+		listItems = m.spinner.View() + " Loading tasks...\n"
+	} else if m.switching != nil {
+		listItems = m.viewSwitch()
+	} else if len(m.filteredList) == 0 {
+		listItems = m.currentTheme().mutedStyle().Render(m.emptyMessage()) + "\n"
+	} else if cols := m.gridColumns(); cols > 1 {
+		listItems = m.viewGrid(cols)
+	} else {
+		listItems = m.viewList()
+	}
+
+	// Help built from the key bindings
+	helpText := "\n" + m.modeLine() + m.helpView()
+
+	if safeMode {
+		helpText = "\nSAFE MODE: execution disabled" + helpText
+	}
+	if len(m.filteredList) < m.totalMatches {
+		helpText = fmt.Sprintf("\nshowing top %d of %d matches", len(m.filteredList), m.totalMatches) + helpText
+	}
+	if m.confirmQuit {
+		helpText = "\n" + m.confirmQuitPrompt() + helpText
+	} else if m.depFocus {
+		helpText = "\n↑/↓: choose dependency • enter: run it • ←: back to tasks" + helpText
+	} else if m.jump != "" {
+		helpText = "\ngo to: " + m.jump + " (enter to select)" + helpText
+	} else if m.checking {
+		helpText = "\nChecking task status..." + helpText
+	} else if m.outdated {
+		helpText = "\n" + m.currentTheme().selectedStyle().Render("Taskfile changed — press ctrl+r to reload") + helpText
+	} else if m.status != "" {
+		helpText = "\n" + m.status + helpText
+	}
+	if len(m.background) > 0 {
+		helpText = "\n" + m.backgroundView() + helpText
+	}
+
+	top := "\n"
+	if config.Header && !m.loading {
+		top = m.headerView() + "\n\n"
+	}
+	return top + filterView + "\n\n" + listItems + m.pinnedView() + helpText
+}
+
+// viewList renders the visible page of tasks one per line, an
+// ultra-compact take on the list's own rendering
+func (m model) viewList() string {
 	var listItems strings.Builder
 	selected := m.list.Index()
-
-	for i, item := range m.filteredList {
-		task := item.(Task)
+	headerStyle := m.currentTheme().mutedStyle().Bold(true)
+	pad := lipgloss.NewStyle().PaddingLeft(m.density.indent())
+
+	// Only render the page of items that fits on screen
+	start, end := m.visiblePage()
+	for i := start; i < end; i++ {
+		task := m.filteredList[i].(Task)
+
+		// Render a header whenever a new group starts
+		if m.grouping != groupFlat {
+			key := groupKey(task, m.grouping)
+			if i == start || key != groupKey(m.filteredList[i-1].(Task), m.grouping) {
+				listItems.WriteString(pad.Render(headerStyle.Render(groupHeader(key, m.grouping))) + "\n")
+			}
+		}
 
 		// Apply styling based on selection state
-		var lineStyle lipgloss.Style
-		if i == selected {
-			lineStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("170")).Bold(true)
-		} else {
-			lineStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("252"))
-		}
+		lineStyle := m.taskStyle(task, i == selected)
 
-		// Render line with task name
-		line := task.Name
+		line, _ := m.taskLabel(task, i)
 
 		// Add description and commands if expanded for selected item
+		showDesc := m.allDescs && !(m.expanded && i == selected)
 		if m.expanded && i == selected {
+			line += detailsView(task, m.depIndex(), m.unfolded[task.Name])
+		} else if showDesc && config.DescSeparator != "" {
+			// Show the description dimmed after the name, when configured
 			if task.Desc != "" {
-				line += "\n    desc: " + task.Desc
-			} else {
-				line += "\n    desc: NO DESCRIPTION"
+				line += m.currentTheme().mutedStyle().Render(config.DescSeparator + task.Desc)
 			}
-			if len(task.Cmds) > 0 {
-				line += "\n    cmds:"
-				for _, cmd := range task.Cmds {
-					line += "\n      " + cmd
-				}
+			showDesc = false
+		}
+		// Show the direct deps of the selected task on a line below it
+		if m.depChips && !m.expanded && i == selected && len(task.Deps) > 0 {
+			line += "\n" + m.currentTheme().mutedStyle().Render("    "+depChips(task.Deps, max(m.width-8, 20)))
+		}
+
+		listItems.WriteString(pad.Render(lineStyle.Render(line)) + "\n")
+
+		// Show every description dimmed on its own line when asked to
+		if showDesc {
+			desc := task.Desc
+			if desc == "" {
+				desc = "no description"
 			}
+			listItems.WriteString(pad.Render(m.currentTheme().mutedStyle().Render("    "+desc)) + "\n")
 		}
 
-		listItems.WriteString(lineStyle.Render(line) + "\n")
+		// Leave room between tasks at the comfortable density
+		if i < end-1 {
+			listItems.WriteString(strings.Repeat("\n", m.density.spacing()))
+		}
 	}
+	return listItems.String()
+}
 
-	// Simple help text
-	helpText := "\n↑/↓: navigate • tab: toggle details • enter: select • q: quit"
+// visiblePage returns the bounds of the page of filteredList holding the
+// selected task. Pages are cut by counting the lines viewList renders for
+// each task, so expanded details, group headers, dep chips, descriptions
+// and the density's spacing all take their room.
+func (m model) visiblePage() (int, int) {
+	n := len(m.filteredList)
+	room := m.height - m.chromeHeight()
+	if m.height == 0 {
+		return 0, n
+	}
 
-	return "\n" + filterView + "\n\n" + listItems.String() + helpText
+	selected, start, used := m.list.Index(), 0, 0
+	for i := range n {
+		lines := m.rowHeight(i, i == start)
+		if i > start {
+			lines += m.density.spacing()
+		}
+		if i > start && used+lines > room {
+			if selected < i {
+				return start, i
+			}
+			start, lines = i, m.rowHeight(i, true)
+			used = 0
+		}
+		used += lines
+	}
+	return start, n
+}
+
+// rowHeight returns the number of lines viewList renders for the task at
+// index i, without the spacing after it. first is set for the first task
+// of a page, which always gets a group header.
+func (m model) rowHeight(i int, first bool) int {
+	task := m.filteredList[i].(Task)
+	selected := i == m.list.Index()
+
+	lines := 1
+	if m.grouping != groupFlat {
+		key := groupKey(task, m.grouping)
+		if first || key != groupKey(m.filteredList[i-1].(Task), m.grouping) {
+			lines++
+		}
+	}
+	if m.expanded && selected {
+		lines += strings.Count(detailsView(task, m.depIndex(), m.unfolded[task.Name]), "\n")
+	} else if m.allDescs && config.DescSeparator == "" {
+		lines++
+	}
+	if m.depChips && !m.expanded && selected && len(task.Deps) > 0 {
+		lines++
+	}
+	return lines
+}
+
+// taskNamed reports whether one of the tasks can be run as name, by its
+// own name or one of its aliases
+func taskNamed(tasks []Task, name string) bool {
+	for _, task := range tasks {
+		if task.Name == name || displayName(task.Name) == name || slices.Contains(task.Aliases, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// unknownTask returns the task name in args when no parsed task has that
+// name. Tasks hidden by exclude patterns still count as known.
+func unknownTask(args []string) (string, bool) {
+	name, _ := splitTaskArgs(args)
+	if name == "" || taskNamed(tasks, name) {
+		return "", false
+	}
+	patterns := append(append([]string{}, config.Exclude...), excludeFlags...)
+	if excluded, err := excludeTasks([]Task{{Name: name}}, patterns); err != nil || len(excluded) == 0 {
+		return "", false
+	}
+	return name, true
+}
+
+// unknownTaskFilter returns the filter to open the TUI with for a task name
+// that doesn't exist. A typo like "buidl" isn't fuzzy-matched by "build",
+// so when nothing matches the name it is replaced by the closest task name,
+// as long as that is only a few edits away.
+func unknownTaskFilter(tasks []Task, name string) string {
+	var items []list.Item
+	for _, task := range tasks {
+		items = append(items, task)
+	}
+	if matched, _, err := fuzzyFilter(items, name, 0, caseSensitive); err != nil || len(matched) > 0 {
+		return name
+	}
+
+	closest, best := name, max(2, len(name)/3)+1
+	for _, task := range tasks {
+		if d := editDistance(name, displayName(task.Name)); d < best {
+			closest, best = displayName(task.Name), d
+		}
+	}
+	return closest
 }
 
 // runTaskDirect passes args directly to task command
 func runTaskDirect(args []string) int {
-	// Create the combined args
-	fullArgs := append(taskCmd.Args, args...)
+	if safeMode {
+		fmt.Fprintln(os.Stderr, "gt: execution disabled in safe mode")
+		return 1
+	}
 
-	// Create and run command
-	cmd := exec.Command(taskCmd.Cmd, fullArgs...)
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
+	stdout, stderr := io.Writer(os.Stdout), io.Writer(os.Stderr)
+
+	// Copy the output to the log file if one was requested
+	if logFile != "" {
+		f, err := os.Create(logFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+		defer f.Close()
+		stdout = io.MultiWriter(os.Stdout, f)
+		stderr = io.MultiWriter(os.Stderr, f)
+	}
+
+	// Run each task on its own so one failing doesn't stop the rest, or so
+	// each task's output can be marked
+	if names, shared, ok := splitEachTask(args); ok {
+		return runEachTask(names, shared, stdout, stderr)
+	}
+
+	return runWithRetries(args, stdout, stderr)
+}
+
+// runWithRetries runs task with args, re-running it with backoff when it
+// fails if --retries was given
+func runWithRetries(args []string, stdout, stderr io.Writer) int {
+	if retries <= 0 {
+		return runTaskOnce(args, stdout, stderr)
+	}
+
+	// Catch ctrl-c so an interrupted task stops the retry loop instead of
+	// killing gt outright
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	for attempt := 1; ; attempt++ {
+		if attempt > 1 {
+			fmt.Fprintf(os.Stderr, "gt: attempt %d/%d\n", attempt, retries+1)
+		}
+
+		code := runTaskOnce(args, stdout, stderr)
+		if code == 0 || attempt > retries || ctx.Err() != nil {
+			return code
+		}
+
+		delay := retryDelay(attempt)
+		fmt.Fprintf(os.Stderr, "gt: task exited with code %d, retrying in %s\n", code, delay)
+		select {
+		case <-ctx.Done():
+			return code
+		case <-time.After(delay):
+		}
+	}
+}
+
+// runTaskOnce runs task with args and returns its exit code
+func runTaskOnce(args []string, stdout, stderr io.Writer) int {
+	// Create command
+	cmd := passthroughCommand(args)
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
 	cmd.Stdin = os.Stdin
 
 	// Run the command and return the exit code
+	debugf("running: %s", strings.Join(cmd.Args, " "))
+	name, rest := splitTaskArgs(args)
+	startRun(name, rest)
+	started := time.Now()
 	err := cmd.Run()
+	finishRun(name, rest, err, time.Since(started))
+	if _, isExitErr := err.(*exec.ExitError); !quiet && runsTask(args) && (err == nil || isExitErr) {
+		fmt.Fprintln(os.Stderr, resultStatus(strings.Join(args, " "), err, time.Since(started)))
+	}
 	if err != nil {
 		// Check if it's an exit error to get the exit code
 		if exitErr, ok := err.(*exec.ExitError); ok {
@@ -503,6 +2434,69 @@ func runTaskDirect(args []string) int {
 	return 0
 }
 
+// nonRunFlags are the task flags that make it report on tasks instead of
+// running them
+var nonRunFlags = map[string]bool{
+	"-l": true, "--list": true,
+	"-a": true, "--list-all": true,
+	"-n": true, "--dry": true,
+	"-i": true, "--init": true,
+	"-h": true, "--help": true,
+	"--summary": true, "--status": true,
+	"--version": true, "--completion": true,
+}
+
+// runsTask reports whether task runs a task for args, which it doesn't
+// without a task name or with flags like --list or --summary
+func runsTask(args []string) bool {
+	name, rest := splitTaskArgs(args)
+	if name == "" {
+		return false
+	}
+	for _, arg := range rest {
+		if arg == "--" {
+			break
+		}
+		if nonRunFlags[arg] {
+			return false
+		}
+	}
+	return true
+}
+
+// passthroughCommand builds the task command run for args when they are
+// passed through to task
+func passthroughCommand(args []string) *exec.Cmd {
+	return taskCmd.command(passthroughTaskfile(args), taskRunArgs(runOptions{yes: assumeYes, verbose: verbose, concurrency: concurrency}, args...)...)
+}
+
+// passthroughTaskfile picks the Taskfile to pass to task for args when
+// Taskfiles were given with --taskfile: the one defining the first task
+// named in args, or else the last one given.
+func passthroughTaskfile(args []string) string {
+	if len(taskfiles) == 0 {
+		return ""
+	}
+	if name, _ := splitTaskArgs(args); name != "" {
+		for _, task := range tasks {
+			if taskNamed([]Task{task}, name) {
+				return task.Taskfile
+			}
+		}
+	}
+	return taskfiles[len(taskfiles)-1]
+}
+
+// retryDelay returns the backoff before the retry following attempt,
+// doubling from one second up to a maximum of 30 seconds
+func retryDelay(attempt int) time.Duration {
+	delay := time.Second << (attempt - 1)
+	if delay <= 0 || delay > 30*time.Second {
+		return 30 * time.Second
+	}
+	return delay
+}
+
 func sortTasksByName(tasks []Task) {
 	sort.Slice(tasks, func(i, j int) bool {
 		return tasks[i].Name < tasks[j].Name
@@ -0,0 +1,613 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestInitErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		err     error
+		message string
+		code    int
+	}{
+		{"task missing", ErrTaskNotFound, "Task is not installed", exitNoTask},
+		{"no Taskfile", ErrNoTaskfile, "No Taskfile found", exitNoTaskfile},
+		{"wrapped no Taskfile", fmt.Errorf("switch: %w", ErrNoTaskfile), "No Taskfile found", exitNoTaskfile},
+		{"no tasks", ErrNoTasks, "No tasks found in Taskfile", exitNoTasks},
+		{"no tasks explained", &noTasksError{reason: "Taskfile.yml has an empty tasks section"}, "No tasks found: Taskfile.yml has an empty tasks section.", exitNoTasks},
+		{"invalid Taskfile", errors.New("yaml: bad indent"), "Error parsing Taskfile: yaml: bad indent", exitError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := initErrorMessage(tt.err); !strings.Contains(got, tt.message) {
+				t.Errorf("initErrorMessage() = %q, want it to contain %q", got, tt.message)
+			}
+			if got := initExitCode(tt.err); got != tt.code {
+				t.Errorf("initExitCode() = %d, want %d", got, tt.code)
+			}
+		})
+	}
+}
+
+func TestMain(m *testing.M) {
+	config = defaultConfig()
+	os.Exit(m.Run())
+}
+
+// inProject writes files, keyed by their slash-separated paths, to a new
+// directory and changes to it. The directory is a repository root, so the
+// search for a Taskfile stops there.
+func inProject(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir, err := filepath.EvalSymlinks(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Mkdir(filepath.Join(dir, ".git"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Chdir(dir)
+	saved := taskfiles
+	t.Cleanup(func() { taskfiles = saved })
+	return dir
+}
+
+// withFakeTask puts a task binary on PATH that only reports its version,
+// and returns its directory, where the arguments of each call are logged
+// to the calls file
+func withFakeTask(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	script := "#!/bin/sh\necho \"$@\" >> \"${0%/*}/calls\"\ncase \"$1\" in --version) echo 'Task version: v3.43.3';; esac\n"
+	if err := os.WriteFile(filepath.Join(dir, "task"), []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir)
+	taskCmdOnce = sync.Once{}
+	t.Cleanup(func() { taskCmdOnce = sync.Once{} })
+	return dir
+}
+
+// taskNames returns the names of the tasks
+func taskNames(tasks []Task) []string {
+	var names []string
+	for _, task := range tasks {
+		names = append(names, task.Name)
+	}
+	return names
+}
+
+func TestInitialize(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string]string
+		want  []string
+		err   error
+	}{
+		{
+			name:  "tasks",
+			files: map[string]string{"Taskfile.yml": "version: '3'\ntasks:\n  test: go test\n  build: go build\n"},
+			want:  []string{"build", "test"},
+		},
+		{
+			name:  "no Taskfile",
+			files: map[string]string{},
+			err:   ErrNoTaskfile,
+		},
+		{
+			name:  "no tasks",
+			files: map[string]string{"Taskfile.yml": "version: '3'\n"},
+			err:   ErrNoTasks,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			withFakeTask(t)
+			inProject(t, tt.files)
+
+			err := initialize()
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("initialize() = %v, want %v", err, tt.err)
+			}
+			if got := taskNames(tasks); err == nil && !slices.Equal(got, tt.want) {
+				t.Errorf("tasks = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseTaskfileNames(t *testing.T) {
+	const taskfile = "version: '3'\ntasks:\n  build: go build\n"
+	tests := []struct {
+		name  string
+		files []string
+		want  string // Taskfile found
+		plain bool   // Whether it is passed to task with --taskfile
+	}{
+		{"extensionless", []string{"Taskfile"}, "Taskfile", true},
+		{"lowercase extensionless", []string{"taskfile"}, "taskfile", true},
+		{"yml before extensionless", []string{"Taskfile", "Taskfile.yml"}, "Taskfile.yml", false},
+		{"dist before extensionless", []string{"Taskfile", "Taskfile.dist.yaml"}, "Taskfile.dist.yaml", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			files := map[string]string{}
+			for _, name := range tt.files {
+				files[name] = taskfile
+			}
+			dir := inProject(t, files)
+			want := filepath.Join(dir, tt.want)
+
+			found, path, err := parseTaskfile()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if path != want {
+				t.Errorf("path = %s, want %s", path, want)
+			}
+			if len(found) != 1 {
+				t.Fatalf("tasks = %v, want build", taskNames(found))
+			}
+			if got := found[0].Taskfile; (got == want) != tt.plain {
+				t.Errorf("Taskfile = %q, want it set only for extensionless Taskfiles", got)
+			}
+		})
+	}
+}
+
+func TestInitializeExtensionlessTaskfile(t *testing.T) {
+	withFakeTask(t)
+	dir := inProject(t, map[string]string{"Taskfile": "version: '3'\ntasks:\n  build: go build\n"})
+	taskfiles = nil
+
+	if err := initialize(); err != nil {
+		t.Fatal(err)
+	}
+	// Passthrough runs need --taskfile too
+	if want := []string{filepath.Join(dir, "Taskfile")}; !slices.Equal(taskfiles, want) {
+		t.Errorf("taskfiles = %v, want %v", taskfiles, want)
+	}
+}
+
+// parseTestTaskfile writes content to a Taskfile in a new project and
+// parses it
+func parseTestTaskfile(t *testing.T, content string) []Task {
+	t.Helper()
+	dir := inProject(t, map[string]string{"Taskfile.yml": content})
+	found, err := parseTaskfileAt(filepath.Join(dir, "Taskfile.yml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return found
+}
+
+func TestParseShorthandTasks(t *testing.T) {
+	found := parseTestTaskfile(t, `version: '3'
+tasks:
+  build: go build ./...
+  check:
+    - go vet ./...
+    - go test ./...
+  full:
+    desc: Everything
+    cmds:
+      - task: check
+`)
+
+	want := map[string][]string{
+		"build": {"go build ./..."},
+		"check": {"go vet ./...", "go test ./..."},
+	}
+	for _, task := range found {
+		cmds, ok := want[task.Name]
+		if !ok {
+			continue
+		}
+		if !slices.Equal(task.Cmds, cmds) {
+			t.Errorf("%s cmds = %q, want %q", task.Name, task.Cmds, cmds)
+		}
+		if task.Desc != "" {
+			t.Errorf("%s desc = %q, want none", task.Name, task.Desc)
+		}
+		delete(want, task.Name)
+	}
+	for name := range want {
+		t.Errorf("shorthand task %s not parsed", name)
+	}
+}
+
+func TestFindSymlinkedTaskfile(t *testing.T) {
+	dir := inProject(t, map[string]string{"shared/Taskfile.yml": "version: '3'\n"})
+	if err := os.Symlink(filepath.Join("shared", "Taskfile.yml"), "Taskfile.yml"); err != nil {
+		t.Skip("symlinks not supported:", err)
+	}
+
+	path, err := findTaskfile()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := filepath.Join(dir, "shared", "Taskfile.yml"); path != want {
+		t.Errorf("findTaskfile() = %s, want the link's target %s", path, want)
+	}
+}
+
+func TestFindDanglingTaskfile(t *testing.T) {
+	inProject(t, nil)
+	if err := os.Symlink("missing.yml", "Taskfile.yml"); err != nil {
+		t.Skip("symlinks not supported:", err)
+	}
+
+	_, err := findTaskfile()
+	if err == nil || !strings.Contains(err.Error(), "symlink to a file that doesn't exist") {
+		t.Errorf("findTaskfile() error = %v, want the broken link reported", err)
+	}
+	if errors.Is(err, ErrNoTaskfile) {
+		t.Error("a broken link was reported as no Taskfile")
+	}
+}
+
+func TestParseTaskCalls(t *testing.T) {
+	found, err := parseTaskfileAt(filepath.Join("testdata", "taskcalls", "Taskfile.yml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{
+		"→ build",
+		"→ build (ARCH=arm64, TARGET=prod)",
+		"→ upload",
+		"echo done",
+	}
+	for _, task := range found {
+		if task.Name == "release" {
+			if !slices.Equal(task.Cmds, want) {
+				t.Errorf("release cmds = %q, want %q", task.Cmds, want)
+			}
+			return
+		}
+	}
+	t.Error("task release not parsed")
+}
+
+// taskfileAbove reports whether dir or one of its parents has a Taskfile
+func taskfileAbove(dir string) bool {
+	for {
+		for _, name := range searchedTaskfileNames {
+			if _, err := os.Lstat(filepath.Join(dir, name)); err == nil {
+				return true
+			}
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return false
+		}
+		dir = parent
+	}
+}
+
+func TestFindTaskfileStopsAtFilesystemRoot(t *testing.T) {
+	config.StopAtRepoRoot = false
+	defer func() { config.StopAtRepoRoot = true }()
+
+	dir := t.TempDir()
+	root := filepath.VolumeName(dir) + string(filepath.Separator)
+	for _, start := range []string{dir, root} {
+		if taskfileAbove(start) {
+			t.Skipf("%s or a parent has a Taskfile", start)
+		}
+		t.Chdir(start)
+		if _, err := findTaskfile(); !errors.Is(err, ErrNoTaskfile) {
+			t.Errorf("findTaskfile() from %s = %v, want ErrNoTaskfile", start, err)
+		}
+	}
+}
+
+// loadedModel returns the TUI model with tasks loaded, sized to show rows
+// lines of tasks
+func loadedModel(t *testing.T, tasks []Task, rows int) model {
+	t.Helper()
+	next, _ := newModel().Update(tasksLoadedMsg{tasks: tasks})
+	m := next.(model)
+	next, _ = m.Update(tea.WindowSizeMsg{Width: 80, Height: rows + m.chromeHeight()})
+	return next.(model)
+}
+
+func TestVisiblePage(t *testing.T) {
+	var tasks []Task
+	for i := range 20 {
+		tasks = append(tasks, Task{Name: fmt.Sprintf("task%02d", i), Desc: "Does a thing", Cmds: []string{"echo one", "echo two"}})
+	}
+	for i := range 10 {
+		tasks[i].Deps = []string{"task19"}
+	}
+
+	m := loadedModel(t, tasks, 10)
+	if start, end := m.visiblePage(); start != 0 || end != 10 {
+		t.Errorf("compact page = %d-%d, want 0-10", start, end)
+	}
+
+	// Comfortable density leaves a blank line between tasks
+	m.density = densityComfortable
+	if start, end := m.visiblePage(); start != 0 || end != 5 {
+		t.Errorf("comfortable page = %d-%d, want 0-5", start, end)
+	}
+	m.density = densityCompact
+
+	// The selected task's deps take a line below it
+	m.depChips = true
+	if start, end := m.visiblePage(); start != 0 || end != 9 {
+		t.Errorf("page with dep chips = %d-%d, want 0-9", start, end)
+	}
+	m.depChips = false
+
+	// Expanded details push tasks to the next page, which still holds the
+	// selection
+	m.expanded = true
+	details := strings.Count(detailsView(tasks[0], m.depIndex(), false), "\n")
+	if start, end := m.visiblePage(); start != 0 || end != max(10-details, 1) {
+		t.Errorf("expanded page = %d-%d, want 0-%d", start, end, max(10-details, 1))
+	}
+	m.list.Select(15)
+	if start, end := m.visiblePage(); start > 15 || end <= 15 {
+		t.Errorf("page %d-%d doesn't hold the selected task 15", start, end)
+	}
+	if got := strings.Count(m.viewList(), "\n"); got > 10 {
+		t.Errorf("viewList rendered %d lines, want at most 10", got)
+	}
+}
+
+func TestGridDensity(t *testing.T) {
+	var tasks []Task
+	for i := range 40 {
+		tasks = append(tasks, Task{Name: fmt.Sprintf("t%02d", i)})
+	}
+	m := loadedModel(t, tasks, 9)
+	m.grid = true
+
+	for _, tt := range []struct {
+		density density
+		rows    int
+	}{
+		{densityCompact, 9},
+		{densityNormal, 9},
+		{densityComfortable, 5},
+	} {
+		m.density = tt.density
+		if got := m.gridRows(); got != tt.rows {
+			t.Errorf("%s: gridRows() = %d, want %d", tt.density, got, tt.rows)
+		}
+		lines := strings.Split(strings.TrimSuffix(m.viewGrid(m.gridColumns()), "\n"), "\n")
+		if len(lines) > 9 {
+			t.Errorf("%s: grid takes %d lines, want at most 9", tt.density, len(lines))
+		}
+		if indent := strings.Repeat(" ", tt.density.indent()); !strings.HasPrefix(lines[0], indent+"t00") {
+			t.Errorf("%s: first row = %q, want it indented by %d", tt.density, lines[0], tt.density.indent())
+		}
+	}
+}
+
+func TestExtractEarlyFlags(t *testing.T) {
+	tests := []struct {
+		args        []string
+		rest        []string
+		interactive bool
+		taskfiles   []string
+	}{
+		{[]string{"-i", "build"}, []string{"build"}, true, nil},
+		{[]string{"build", "-i"}, []string{"build", "-i"}, false, nil},
+		{[]string{"-t", "ci.yml", "build", "-t", "x"}, []string{"build", "-t", "x"}, false, []string{"ci.yml"}},
+		{[]string{"-d", "sub", "-i"}, []string{"-d", "sub"}, true, nil},
+		{[]string{"-o", "prefixed", "--interactive", "lint", "-i"}, []string{"-o", "prefixed", "lint", "-i"}, true, nil},
+		{[]string{"-v", "--", "-i"}, []string{"-v", "--", "-i"}, false, nil},
+	}
+	for _, tt := range tests {
+		interactive, taskfiles = false, nil
+		rest := extractEarlyFlags(rootCmd, tt.args)
+		if !slices.Equal(rest, tt.rest) || interactive != tt.interactive || !slices.Equal(taskfiles, tt.taskfiles) {
+			t.Errorf("extractEarlyFlags(%q) = %q, interactive %v, taskfiles %q; want %q, %v, %q",
+				tt.args, rest, interactive, taskfiles, tt.rest, tt.interactive, tt.taskfiles)
+		}
+	}
+	interactive, taskfiles = false, nil
+}
+
+func TestRunsTask(t *testing.T) {
+	tests := []struct {
+		args []string
+		runs bool
+	}{
+		{[]string{"build"}, true},
+		{[]string{"-f", "build", "lint"}, true},
+		{[]string{"build", "--", "-l"}, true},
+		{[]string{"-l"}, false},
+		{[]string{"--list-all", "--json"}, false},
+		{[]string{"--version"}, false},
+		{[]string{"--summary", "build"}, false},
+		{[]string{"build", "--dry"}, false},
+		{[]string{"-d", "sub"}, false},
+	}
+	for _, tt := range tests {
+		if got := runsTask(tt.args); got != tt.runs {
+			t.Errorf("runsTask(%q) = %v, want %v", tt.args, got, tt.runs)
+		}
+	}
+}
+
+func TestRanTasksByTaskfile(t *testing.T) {
+	tasks := []Task{{Name: "build", Taskfile: "a.yml"}, {Name: "build", Taskfile: "b.yml"}}
+	m := loadedModel(t, tasks, 10)
+	m.markRan(tasks[0])
+	m.upToDate = map[string]bool{taskID(tasks[1]): true}
+
+	m.hideRan = true
+	m.applyFilter()
+	if len(m.filteredList) != 1 || m.filteredList[0].(Task).Taskfile != "b.yml" {
+		t.Errorf("hiding ran tasks left %v, want only b.yml's build", m.filteredList)
+	}
+
+	m.hideRan, m.staleOnly = false, true
+	m.applyFilter()
+	if len(m.filteredList) != 1 || m.filteredList[0].(Task).Taskfile != "a.yml" {
+		t.Errorf("stale tasks are %v, want only a.yml's build", m.filteredList)
+	}
+}
+
+func TestInitializeSkipsTaskVersion(t *testing.T) {
+	dir := withFakeTask(t)
+	inProject(t, map[string]string{"Taskfile.yml": "version: '3'\ntasks:\n  build: go build\n"})
+
+	if err := initialize(); err != nil {
+		t.Fatalf("initialize() = %v", err)
+	}
+	if calls, err := os.ReadFile(filepath.Join(dir, "calls")); err == nil {
+		t.Errorf("initialize ran task: %q", calls)
+	}
+}
+
+func TestUnknownTaskFilter(t *testing.T) {
+	tasks := []Task{{Name: "build"}, {Name: "lint"}, {Name: "docker:push"}}
+	tests := []struct {
+		name, filter string
+	}{
+		{"buidl", "build"},
+		{"bld", "bld"},
+		{"dcoker:push", "docker:push"},
+		{"deploy", "deploy"},
+	}
+	for _, tt := range tests {
+		if got := unknownTaskFilter(tasks, tt.name); got != tt.filter {
+			t.Errorf("unknownTaskFilter(%q) = %q, want %q", tt.name, got, tt.filter)
+		}
+	}
+}
+
+func TestTaskInfoOnlyForDetails(t *testing.T) {
+	withFakeTask(t)
+	tasks := []Task{{Name: "build", Taskfile: "a.yml"}, {Name: "build", Taskfile: "b.yml"}}
+	m := loadedModel(t, tasks, 10)
+	if cmd := m.askTaskInfo(); cmd != nil {
+		t.Error("task was asked for the tasks' metadata with the details closed")
+	}
+
+	next, cmd := m.Update(tea.KeyMsg{Type: tea.KeyTab})
+	m = next.(model)
+	if cmd == nil {
+		t.Error("opening the details didn't ask task for the tasks' metadata")
+	}
+	if cmd := m.askTaskInfo(); cmd != nil {
+		t.Error("task was asked for the tasks' metadata twice")
+	}
+
+	upToDate := true
+	next, _ = m.Update(taskInfoMsg{taskID(tasks[1]): {UpToDate: &upToDate}})
+	m = next.(model)
+	for _, item := range m.allItems {
+		task := item.(Task)
+		if (task.Info != nil) != (task.Taskfile == "b.yml") {
+			t.Errorf("%s's build has info %v, want it only for b.yml's", task.Taskfile, task.Info)
+		}
+	}
+}
+
+func TestDocumentedTasks(t *testing.T) {
+	tasks := []Task{{Name: "build", Desc: "Build the binary"}, {Name: "helper"}, {Name: "lint", Desc: "Run the linters"}}
+
+	var out strings.Builder
+	printTaskList(&out, tasks, false)
+	if got := out.String(); !strings.Contains(got, "build") || !strings.Contains(got, "lint") || strings.Contains(got, "helper") {
+		t.Errorf("gt list printed %q, want only build and lint", got)
+	}
+	out.Reset()
+	printTaskList(&out, tasks, true)
+	if got := out.String(); !strings.Contains(got, "helper") {
+		t.Errorf("gt list --all printed %q, want helper too", got)
+	}
+
+	m := loadedModel(t, tasks, 10)
+	if len(m.filteredList) != 3 {
+		t.Fatalf("the TUI lists %d tasks, want all 3", len(m.filteredList))
+	}
+	next, _ := m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	next, _ = next.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("a")})
+	m = next.(model)
+	if names := taskNames(listedItems(m)); !slices.Equal(names, []string{"build", "lint"}) {
+		t.Errorf("a left %q listed, want build and lint", names)
+	}
+	m.filter.SetValue("l")
+	m.applyFilter()
+	if names := taskNames(listedItems(m)); slices.Contains(names, "helper") {
+		t.Errorf("filtering documented tasks listed %q", names)
+	}
+	m.documented = false
+	m.applyFilter()
+	if names := taskNames(listedItems(m)); !slices.Contains(names, "helper") {
+		t.Errorf("filtering all tasks listed %q, want helper too", names)
+	}
+}
+
+// listedItems returns the tasks the TUI lists
+func listedItems(m model) []Task {
+	var listed []Task
+	for _, item := range m.filteredList {
+		if task, ok := item.(Task); ok {
+			listed = append(listed, task)
+		}
+	}
+	return listed
+}
+
+func TestShowTaskListUsesTheRootTaskfile(t *testing.T) {
+	dir := withFakeTask(t)
+	project := inProject(t, map[string]string{"ci.yml": "version: '3'\ntasks:\n  build: go build\n"})
+	taskfiles = []string{"ci.yml"}
+
+	next, _ := loadedModel(t, []Task{{Name: "build", Taskfile: "ci.yml"}}, 10).showTaskList()
+	for range next.(model).run.events {
+	}
+	calls, err := os.ReadFile(filepath.Join(dir, "calls"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "--taskfile " + filepath.Join(project, "ci.yml") + " --list-all"; !strings.Contains(string(calls), want) {
+		t.Errorf("task was called with %q, want %q", calls, want)
+	}
+}
+
+func TestWriteCommands(t *testing.T) {
+	dir := withFakeTask(t)
+	project := inProject(t, map[string]string{"Taskfile.yml": "version: '3'\ntasks:\n  lint: golint\n  build: go build\n"})
+	savedEnv, savedFlags := config.Env, envFlags
+	t.Cleanup(func() { config.Env, envFlags, echoTasks = savedEnv, savedFlags, false })
+	config.Env = map[string]string{"GOFLAGS": "-v -x"}
+	envFlags = []string{"CI=1"}
+	echoTasks = true
+
+	var out strings.Builder
+	if err := writeCommands(&out, []string{"-d", "sub", "lint", "build"}); err != nil {
+		t.Fatal(err)
+	}
+	task := filepath.Join(dir, "task")
+	want := fmt.Sprintf("cd %[1]s && GOFLAGS='-v -x' CI=1 %[2]s -d sub lint\ncd %[1]s && GOFLAGS='-v -x' CI=1 %[2]s -d sub build\n", project, task)
+	if out.String() != want {
+		t.Errorf("writeCommands() wrote\n%s\nwant\n%s", out.String(), want)
+	}
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"regexp"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// markdownSpan finds the inline markdown rendered in descriptions: code
+// spans, **bold** and *emphasis*, without nesting. Emphasis must not touch
+// a word on the outside, so 2*3*4 stays as is.
+var markdownSpan = regexp.MustCompile("`([^`]+)`|\\*\\*([^*]+)\\*\\*|(^|[^\\w*])\\*([^*\\s](?:[^*]*[^*\\s])?)\\*($|[^\\w*])")
+
+// SGR sequences turning text attributes on and off. Unlike a full reset,
+// turning one attribute off keeps the style of the rest of the line.
+const (
+	boldOn       = "\x1b[1m"
+	boldOff      = "\x1b[22m"
+	italicOn     = "\x1b[3m"
+	italicOff    = "\x1b[23m"
+	underlineOn  = "\x1b[4m"
+	underlineOff = "\x1b[24m"
+)
+
+// renderMarkdown renders the inline markdown of a description with text
+// attributes, leaving the rest as is. Without styling, e.g. when output
+// isn't a terminal, the markers are dropped to leave plain text.
+func renderMarkdown(text string) string {
+	styled := lipgloss.NewStyle().Bold(true).Render("x") != "x"
+
+	return markdownSpan.ReplaceAllStringFunc(text, func(span string) string {
+		match := markdownSpan.FindStringSubmatch(span)
+		code, bold, before, em, after := match[1], match[2], match[3], match[4], match[5]
+		switch {
+		case !styled:
+			return code + bold + before + em + after
+		case code != "":
+			return underlineOn + code + underlineOff
+		case bold != "":
+			return boldOn + bold + boldOff
+		default:
+			return before + italicOn + em + italicOff + after
+		}
+	})
+}
@@ -0,0 +1,73 @@
+package main
+
+import "strings"
+
+// filterModes describes how the filter is applied: the match mode and any
+// #tag or namespace scope it has
+func (m model) filterModes() []string {
+	var tags []string
+	var query []string
+	for _, word := range strings.Fields(m.filter.Value()) {
+		if tag, ok := strings.CutPrefix(word, "#"); ok {
+			tags = append(tags, tag)
+		} else {
+			query = append(query, word)
+		}
+	}
+	rest := strings.Join(query, " ")
+
+	var modes []string
+	switch {
+	case strings.HasPrefix(rest, "'"):
+		modes = append(modes, "exact")
+	case strings.HasPrefix(rest, "/"):
+		modes = append(modes, "regexp")
+	default:
+		modes = append(modes, "fuzzy")
+	}
+	if len(tags) > 0 {
+		modes = append(modes, "#"+strings.Join(tags, " #"))
+	}
+	if _, _, _, ok := namespaceScope(m.allItems, rest); ok {
+		modes = append(modes, "in "+rest[:strings.LastIndex(rest, ":")+1])
+	}
+	return modes
+}
+
+// modeLine summarizes the active filter mode and view toggles, like
+// [fuzzy|documented|group:file], or is empty with hide_modes configured
+func (m model) modeLine() string {
+	if config.HideModes {
+		return ""
+	}
+
+	modes := m.filterModes()
+	for _, toggle := range []struct {
+		on   bool
+		name string
+	}{
+		{m.documented, "documented"},
+		{m.staleOnly, "stale"},
+		{m.hideRan, "hide done"},
+		{m.grouping != groupFlat, "group:" + m.grouping.String()},
+		{m.grid, "grid"},
+		{m.yes, "yes"},
+		{m.verbose, "verbose"},
+		{m.matchCase, "case"},
+		{m.fromTask, "from task"},
+		{m.concurrency > 0, "concurrency:" + concurrencyName(m.concurrency)},
+	} {
+		if toggle.on {
+			modes = append(modes, toggle.name)
+		}
+	}
+	return "[" + strings.Join(modes, "|") + "] "
+}
+
+// caseMode describes how the filter treats case, for the help line
+func (m model) caseMode() string {
+	if m.matchCase {
+		return "sensitive"
+	}
+	return "insensitive"
+}
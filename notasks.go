@@ -0,0 +1,92 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// noTasksError is ErrNoTasks with the reason the Taskfiles define no tasks
+type noTasksError struct {
+	reason string
+}
+
+func (e *noTasksError) Error() string {
+	return ErrNoTasks.Error() + ": " + e.reason
+}
+
+func (e *noTasksError) Unwrap() error {
+	return ErrNoTasks
+}
+
+// explainNoTasks returns ErrNoTasks with the reason the Taskfiles at paths
+// define no tasks: whether they have no tasks and no includes, or includes
+// that were skipped or add no tasks either
+func explainNoTasks(paths []string) error {
+	var reasons []string
+	for _, path := range paths {
+		if reason := noTasksReason(path); reason != "" {
+			reasons = append(reasons, reason)
+		}
+	}
+	if len(reasons) == 0 {
+		return ErrNoTasks
+	}
+	return &noTasksError{reason: strings.Join(reasons, "; ")}
+}
+
+// allExcluded returns ErrNoTasks for when the exclude patterns hid every
+// one of the n tasks found
+func allExcluded(n int, patterns []string) error {
+	return &noTasksError{reason: fmt.Sprintf("all %d tasks are hidden by the exclude patterns %s; run with --show-all to see them", n, strings.Join(patterns, ", "))}
+}
+
+// noTasksReason explains why the Taskfile at path defines no tasks, or
+// returns "" when it can't be read
+func noTasksReason(path string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	var taskfile map[string]interface{}
+	if err := yaml.Unmarshal(data, &taskfile); err != nil {
+		return ""
+	}
+	includes, err := parseIncludes(taskfile)
+	if err != nil {
+		return ""
+	}
+
+	name := taskfileTag(path)
+	tasksMap, _ := taskfile["tasks"].(map[string]interface{})
+	var own string
+	switch _, ok := taskfile["tasks"]; {
+	case len(tasksMap) > 0:
+		own = name + " only has internal tasks, which can't be run directly,"
+	case ok:
+		own = name + " has an empty tasks section"
+	default:
+		own = name + " has no tasks section"
+	}
+	if len(includes) == 0 {
+		return own + " and no includes"
+	}
+
+	var skipped []string
+	for _, inc := range includes {
+		included, err := resolveInclude(filepath.Dir(path), inc.taskfile)
+		switch {
+		case inc.internal:
+			skipped = append(skipped, fmt.Sprintf("%q is internal", inc.namespace))
+		case errors.Is(err, os.ErrNotExist) && inc.optional:
+			skipped = append(skipped, fmt.Sprintf("%q is optional and %s doesn't exist", inc.namespace, inc.taskfile))
+		case err == nil:
+			skipped = append(skipped, fmt.Sprintf("%q (%s) defines no tasks", inc.namespace, taskfileTag(included)))
+		}
+	}
+	return fmt.Sprintf("%s and its includes add none: %s", own, strings.Join(skipped, ", "))
+}
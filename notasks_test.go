@@ -0,0 +1,96 @@
+package main
+
+import (
+	"errors"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestNoTasksReason(t *testing.T) {
+	tests := []struct {
+		name   string
+		files  map[string]string
+		reason string
+	}{
+		{
+			name:   "no tasks section",
+			files:  map[string]string{"Taskfile.yml": "version: '3'\n"},
+			reason: "Taskfile.yml has no tasks section and no includes",
+		},
+		{
+			name:   "empty tasks section",
+			files:  map[string]string{"Taskfile.yml": "version: '3'\ntasks: {}\n"},
+			reason: "Taskfile.yml has an empty tasks section and no includes",
+		},
+		{
+			name:   "internal tasks",
+			files:  map[string]string{"Taskfile.yml": "version: '3'\ntasks:\n  setup:\n    internal: true\n    cmds: [echo]\n"},
+			reason: "Taskfile.yml only has internal tasks, which can't be run directly, and no includes",
+		},
+		{
+			name: "includes without tasks",
+			files: map[string]string{
+				"Taskfile.yml": "version: '3'\nincludes:\n  lib: ./lib.yml\n  local:\n    taskfile: ./local.yml\n    optional: true\n",
+				"lib.yml":      "version: '3'\n",
+			},
+			reason: `Taskfile.yml has no tasks section and its includes add none: "lib" (lib.yml) defines no tasks, "local" is optional and ./local.yml doesn't exist`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			withFakeTask(t)
+			dir := inProject(t, tt.files)
+
+			err := initialize()
+			var noTasks *noTasksError
+			if !errors.As(err, &noTasks) {
+				t.Fatalf("initialize() = %v, want a noTasksError", err)
+			}
+			// Taskfiles are named relative to where gt was started
+			if got := strings.ReplaceAll(noTasks.reason, dir+string(filepath.Separator), ""); got != tt.reason {
+				t.Errorf("reason = %q\nwant %q", got, tt.reason)
+			}
+		})
+	}
+}
+
+func TestIncludesOnlyTaskfile(t *testing.T) {
+	withFakeTask(t)
+	inProject(t, map[string]string{
+		"Taskfile.yml":      "version: '3'\nincludes:\n  docs: ./docs\n  lib: ./lib.yml\n",
+		"docs/Taskfile.yml": "version: '3'\ntasks:\n  serve: mkdocs serve\n",
+		"lib.yml":           "version: '3'\ntasks:\n  test: go test\n",
+	})
+
+	if err := initialize(); err != nil {
+		t.Fatalf("initialize() = %v", err)
+	}
+	if got, want := taskNames(tasks), []string{"docs:serve", "lib:test"}; !slices.Equal(got, want) {
+		t.Errorf("tasks = %v, want %v", got, want)
+	}
+}
+
+func TestAllTasksExcluded(t *testing.T) {
+	withFakeTask(t)
+	inProject(t, map[string]string{"Taskfile.yml": "version: '3'\ntasks:\n  gen:a: echo\n  gen:b: echo\n"})
+	saved := excludeFlags
+	excludeFlags = []string{"gen:*"}
+	defer func() { excludeFlags = saved }()
+
+	err := initialize()
+	var noTasks *noTasksError
+	if !errors.As(err, &noTasks) {
+		t.Fatalf("initialize() = %v, want a noTasksError", err)
+	}
+	if want := "all 2 tasks are hidden by the exclude patterns gen:*; run with --show-all to see them"; noTasks.reason != want {
+		t.Errorf("reason = %q, want %q", noTasks.reason, want)
+	}
+
+	showAll = true
+	defer func() { showAll = false }()
+	if err := initialize(); err != nil {
+		t.Errorf("initialize() with --show-all = %v", err)
+	}
+}
@@ -0,0 +1,300 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// killGracePeriod is how long a cancelled task gets to exit after SIGTERM
+// before it is killed
+const killGracePeriod = 3 * time.Second
+
+// taskRun tracks a task running with its output captured inside the TUI
+type taskRun struct {
+	name      string // Label shown for the run
+	task      string // Name of the task being run, empty for other task commands
+	retry     Task   // Task to run again when retrying
+	scratch   string // Ad-hoc command being run, empty for tasks
+	cmd       *exec.Cmd
+	events    chan tea.Msg
+	output    []string
+	done      bool
+	err       error
+	cancelled bool
+	started   time.Time
+	elapsed   time.Duration
+	before    fileTimes // The task's generated files before it ran
+	artifacts []string  // Generated files created or updated by the run
+}
+
+// runOutputMsg carries a line of output from the running task
+type runOutputMsg string
+
+// runFinishedMsg is sent once the running task has exited
+type runFinishedMsg struct {
+	err error
+}
+
+// runKillMsg fires when the grace period after a cancel has elapsed
+type runKillMsg struct {
+	run *taskRun
+}
+
+// startCapturedRun starts task with the given Taskfile and arguments, with
+// stdout and stderr piped into the TUI
+func startCapturedRun(name, taskfile string, args []string) (*taskRun, error) {
+	return startCapturedCommand(name, taskCmd.command(taskfile, args...))
+}
+
+// startCapturedCommand starts cmd with stdout and stderr piped into the TUI
+func startCapturedCommand(name string, cmd *exec.Cmd) (*taskRun, error) {
+	// Don't wait forever on pipes held open by orphaned grandchildren
+	cmd.WaitDelay = killGracePeriod
+
+	pr, pw := io.Pipe()
+	cmd.Stdout = pw
+	cmd.Stderr = pw
+
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+
+	run := &taskRun{
+		name:    name,
+		cmd:     cmd,
+		events:  make(chan tea.Msg),
+		started: time.Now(),
+	}
+
+	waitErr := make(chan error, 1)
+	go func() {
+		err := cmd.Wait()
+		pw.Close()
+		waitErr <- err
+	}()
+
+	go func() {
+		scanner := bufio.NewScanner(pr)
+		scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+		for scanner.Scan() {
+			run.events <- runOutputMsg(scanner.Text())
+		}
+		// Drain anything the scanner gave up on so the writer never blocks
+		io.Copy(io.Discard, pr)
+		pr.Close()
+
+		run.events <- runFinishedMsg{err: <-waitErr}
+		close(run.events)
+	}()
+
+	return run, nil
+}
+
+// waitForRunEvent returns a command that delivers the next event of the run
+func waitForRunEvent(run *taskRun) tea.Cmd {
+	return func() tea.Msg {
+		msg, ok := <-run.events
+		if !ok {
+			return nil
+		}
+		return msg
+	}
+}
+
+// cancel asks the task to stop with SIGTERM and schedules a SIGKILL
+// after the grace period
+func (r *taskRun) cancel() tea.Cmd {
+	if r.done || r.cancelled {
+		return nil
+	}
+	r.cancelled = true
+
+	// Signals other than Kill aren't supported everywhere (e.g. Windows)
+	if err := r.cmd.Process.Signal(syscall.SIGTERM); err != nil {
+		r.cmd.Process.Kill()
+		return nil
+	}
+
+	return tea.Tick(killGracePeriod, func(time.Time) tea.Msg {
+		return runKillMsg{run: r}
+	})
+}
+
+// updateRun handles events while the output view is shown
+func (m model) updateRun(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmds []tea.Cmd
+
+	switch msg := msg.(type) {
+	case runOutputMsg:
+		m.run.output = append(m.run.output, string(msg))
+		atBottom := m.output.AtBottom()
+		m.output.SetContent(strings.Join(m.run.output, "\n"))
+		if atBottom {
+			m.output.GotoBottom()
+		}
+		return m, waitForRunEvent(m.run)
+
+	case runFinishedMsg:
+		m.run.done = true
+		m.run.err = msg.err
+		m.run.elapsed = time.Since(m.run.started)
+		if m.run.task != "" {
+			finishRun(m.run.task, nil, msg.err, m.run.elapsed)
+			m.markRan(m.run.retry)
+			if msg.err == nil {
+				m.run.artifacts = generatedSince(m.run.retry, m.run.before)
+			}
+		}
+		if m.run.scratch != "" {
+			recordScratch(m.run.scratch, msg.err)
+		}
+		if m.run.cancelled {
+			// Go straight back to the list after a cancel
+			m.status = "task cancelled"
+			m.run = nil
+		}
+		return m, nil
+
+	case runKillMsg:
+		if msg.run == m.run && !m.run.done {
+			m.run.cmd.Process.Kill()
+		}
+		return m, nil
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "ctrl+c":
+			if !m.run.done {
+				m.run.cmd.Process.Kill()
+			}
+			return m, tea.Quit
+		case "ctrl+x":
+			return m, m.run.cancel()
+		case "r":
+			// Retry a failed task
+			if m.run.done && m.run.err != nil && m.run.task != "" {
+				return m.startCaptured(m.run.retry)
+			}
+		case "esc", "enter", "q":
+			if m.run.done {
+				m.status = runStatus(m.run)
+				m.run = nil
+				return m, nil
+			}
+		}
+	}
+
+	var vpCmd tea.Cmd
+	m.output, vpCmd = m.output.Update(msg)
+	cmds = append(cmds, vpCmd)
+
+	return m, tea.Batch(cmds...)
+}
+
+// runStatus summarizes how a captured run ended
+func runStatus(run *taskRun) string {
+	return resultStatus(run.name, run.err, run.elapsed) + artifactsNote(run.artifacts)
+}
+
+// resultStatus summarizes how a run of name ended and how long it took
+func resultStatus(name string, err error, elapsed time.Duration) string {
+	if err == nil {
+		return fmt.Sprintf("✓ %s succeeded in %s", name, formatDuration(elapsed))
+	}
+	if exitErr, ok := err.(*exec.ExitError); ok {
+		return fmt.Sprintf("✗ %s failed (exit code %d) after %s", name, exitErr.ExitCode(), formatDuration(elapsed))
+	}
+	return fmt.Sprintf("✗ %s failed: %v", name, err)
+}
+
+// formatDuration rounds a run time for display, e.g. 4.2s or 350ms
+func formatDuration(d time.Duration) string {
+	if d < time.Second {
+		return d.Round(time.Millisecond).String()
+	}
+	return d.Round(100 * time.Millisecond).String()
+}
+
+// viewRun renders the captured output of the running task
+func (m model) viewRun() string {
+	titleStyle := m.currentTheme().selectedStyle().Padding(0, 1)
+
+	var title, help string
+	switch {
+	case !m.run.done && m.run.cancelled:
+		title = "Cancelling " + m.run.name + "..."
+		help = "ctrl+c: kill and quit"
+	case !m.run.done:
+		title = "Running " + m.run.name + "..."
+		help = "↑/↓: scroll • ctrl+x: cancel task • ctrl+c: kill and quit"
+	case m.run.err != nil && m.run.task != "":
+		title = runStatus(m.run)
+		help = "↑/↓: scroll • r: retry • enter/esc: back to list"
+	default:
+		title = runStatus(m.run)
+		help = "↑/↓: scroll • enter/esc: back to list"
+	}
+
+	return "\n" + titleStyle.Render(title) + "\n\n" + m.output.View() + "\n\n" + help
+}
+
+// newOutputViewport creates the viewport used to show captured task output
+func newOutputViewport(width, height int) viewport.Model {
+	return viewport.New(width, max(height-6, 1))
+}
+
+// runCaptured starts the selected task with its output shown inside the TUI
+func (m model) runCaptured() (tea.Model, tea.Cmd) {
+	task, ok := m.list.SelectedItem().(Task)
+	if !ok || len(m.filteredList) == 0 {
+		return m, nil
+	}
+	return m.startCaptured(task)
+}
+
+// startCaptured runs the task with its output shown inside the TUI
+func (m model) startCaptured(task Task) (tea.Model, tea.Cmd) {
+	if safeMode {
+		return m.refuseInSafeMode()
+	}
+	selectRun(task.Name)
+	before := generatedFiles(task)
+	run, err := startCapturedRun(task.Name, task.Taskfile, taskRunArgs(m.runOptions(), task.Name))
+	if err != nil {
+		m.status = fmt.Sprintf("✗ %s failed to start: %v", task.Name, err)
+		return m, nil
+	}
+	startRun(task.Name, nil)
+	run.task = task.Name
+	run.retry = task
+	run.before = before
+
+	m.run = run
+	m.status = ""
+	m.output = newOutputViewport(m.width, m.height)
+	return m, waitForRunEvent(run)
+}
+
+// showTaskList shows task's own listing of all tasks in the output view, to
+// compare against what gt parsed. Task lists the root Taskfile gt parsed,
+// which it may not find by itself, such as one given with --taskfile.
+func (m model) showTaskList() (tea.Model, tea.Cmd) {
+	run, err := startCapturedRun("task --list-all", rootTaskfile(), []string{"--list-all"})
+	if err != nil {
+		m.status = fmt.Sprintf("✗ task --list-all failed to start: %v", err)
+		return m, nil
+	}
+
+	m.run = run
+	m.status = ""
+	m.output = newOutputViewport(m.width, m.height)
+	return m, waitForRunEvent(run)
+}
@@ -0,0 +1,55 @@
+package main
+
+import "strings"
+
+// togglePin pins the details of the selected task below the list, where
+// they stay while browsing other tasks, or unpins them if already pinned
+func (m *model) togglePin() {
+	if m.pinned != nil {
+		m.pinned = nil
+		m.status = "preview unpinned"
+	} else if task, ok := m.list.SelectedItem().(Task); ok && len(m.filteredList) > 0 {
+		m.pinned = &task
+		m.status = "preview pinned to " + task.Name
+	}
+	m.list.SetSize(m.width, m.height-m.chromeHeight())
+}
+
+// pinnedView renders the details of the pinned task, or nothing when no
+// task is pinned
+func (m model) pinnedView() string {
+	if m.pinned == nil {
+		return ""
+	}
+	title := m.currentTheme().mutedStyle().Bold(true).Render("pinned: " + m.pinned.Name)
+	return "\n" + title + detailsView(*m.pinned, -1, m.unfolded[m.pinned.Name]) + "\n"
+}
+
+// pinnedHeight is the number of lines taken by the pinned preview
+func (m model) pinnedHeight() int {
+	return strings.Count(m.pinnedView(), "\n")
+}
+
+// foldable reports whether the selected task's command list is long enough
+// to be folded
+func (m model) foldable() bool {
+	task, ok := m.list.SelectedItem().(Task)
+	return ok && len(m.filteredList) > 0 && len(task.Cmds) > foldThreshold
+}
+
+// toggleFold shows all the commands of the selected task when its command
+// list is folded, or folds it again, opening the details to show them
+func (m *model) toggleFold() {
+	if !m.foldable() {
+		return
+	}
+	task := m.list.SelectedItem().(Task)
+	if m.unfolded == nil {
+		m.unfolded = map[string]bool{}
+	}
+	m.unfolded[task.Name] = !m.unfolded[task.Name]
+	m.expanded = true
+
+	// The pinned preview may have changed size
+	m.list.SetSize(m.width, m.height-m.chromeHeight())
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"sync"
+	"time"
+)
+
+// profile reports how long finding and parsing the Taskfiles took, set
+// with --profile
+var profile bool
+
+// profilePhases are the phases timed with --profile, in report order
+var profilePhases = []string{"task binary", "discovery", "read and parse", "include resolution"}
+
+// profileTimes sums the time spent in each phase since the last report.
+// Includes are parsed concurrently, so it is safe for concurrent use.
+var profileTimes = struct {
+	sync.Mutex
+	spent map[string]time.Duration
+	count map[string]int
+}{spent: map[string]time.Duration{}, count: map[string]int{}}
+
+// profiled adds the time since start to phase; use it with defer
+func profiled(phase string, start time.Time) {
+	if !profile {
+		return
+	}
+	elapsed := time.Since(start)
+	profileTimes.Lock()
+	defer profileTimes.Unlock()
+	profileTimes.spent[phase] += elapsed
+	profileTimes.count[phase]++
+}
+
+// reportProfile logs the time spent in each phase and in total, and starts
+// over for the next load. Phases running concurrently are summed, so they
+// can add up to more than the total.
+func reportProfile(total time.Duration) {
+	if !profile {
+		return
+	}
+	profileTimes.Lock()
+	defer profileTimes.Unlock()
+	for _, phase := range profilePhases {
+		if n := profileTimes.count[phase]; n > 0 {
+			debugLog.Printf("profile: %-18s %10v (%d×)", phase, profileTimes.spent[phase].Round(time.Microsecond), n)
+		}
+	}
+	debugLog.Printf("profile: %-18s %10v", "total", total.Round(time.Microsecond))
+	clear(profileTimes.spent)
+	clear(profileTimes.count)
+}
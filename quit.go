@@ -0,0 +1,33 @@
+package main
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// quit quits right away, unless tasks are marked and the confirm_quit
+// option asks before discarding them
+func (m model) quit() (tea.Model, tea.Cmd) {
+	if config.ConfirmQuit && len(m.marked) > 0 {
+		m.confirmQuit = true
+		return m, nil
+	}
+	return m, tea.Quit
+}
+
+// updateConfirmQuit handles the answer to the quit confirmation. Anything
+// but y keeps gt open.
+func (m model) updateConfirmQuit(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	m.confirmQuit = false
+	switch msg.String() {
+	case "y", "Y", "ctrl+c":
+		return m, tea.Quit
+	}
+	return m, nil
+}
+
+// confirmQuitPrompt asks whether to quit and lose the marked tasks
+func (m model) confirmQuitPrompt() string {
+	return fmt.Sprintf("Discard selection (%d marked) and quit? (y/n)", len(m.marked))
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+	"text/tabwriter"
+)
+
+// showTaskResolution prints how the task binary was chosen and exits
+var showTaskResolution bool
+
+// taskCandidate is one of the ways gt can run task
+type taskCandidate struct {
+	label string
+	cmd   TaskCommand
+	where string // Path of the binary, empty when unavailable
+}
+
+// taskCandidates checks both ways of running task, in order of preference
+func taskCandidates() []taskCandidate {
+	candidates := []taskCandidate{
+		{label: "task", cmd: TaskCommand{Cmd: "task", Args: []string{}}},
+		{label: "go tool task", cmd: TaskCommand{Cmd: "go", Args: []string{"tool", "task"}}},
+	}
+
+	if path, err := exec.LookPath("task"); err == nil {
+		candidates[0].where = path
+	}
+	if exec.Command("go", "tool", "task", "--help").Run() == nil {
+		candidates[1].where = "go.mod tool"
+	}
+	return candidates
+}
+
+// version returns the first line of task's --version output
+func (t TaskCommand) version() string {
+	out, err := t.command("", "--version").Output()
+	if err != nil {
+		return "unknown version"
+	}
+	line, _, _ := strings.Cut(strings.TrimSpace(string(out)), "\n")
+	return line
+}
+
+// printTaskResolution lists the ways of running task that were found, with
+// their versions, and which one gt uses
+func printTaskResolution(w io.Writer) {
+	candidates := taskCandidates()
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	var found []string
+	for _, c := range candidates {
+		if c.where == "" {
+			fmt.Fprintf(tw, "%s\tnot found\n", c.label)
+			continue
+		}
+		found = append(found, c.label)
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", c.label, c.where, c.cmd.version())
+	}
+	tw.Flush()
+
+	switch len(found) {
+	case 0:
+		fmt.Fprintln(w, "\nNeither was found; install task or add it as a go tool")
+	case 1:
+		fmt.Fprintf(w, "\nUsing %s\n", found[0])
+	default:
+		fmt.Fprintf(w, "\nBoth were found; using %s, which takes precedence\n", found[0])
+	}
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// printResolved dumps the parsed tasks instead of running anything
+var printResolved bool
+
+// printCmd prints the task command instead of running it
+var printCmd bool
+
+// jsonOutput selects JSON over YAML for --print-resolved
+var jsonOutput bool
+
+// writeResolved writes the tasks as gt sees them, after merging Taskfiles,
+// as YAML or JSON
+func writeResolved(w io.Writer, tasks []Task, asJSON bool) error {
+	if asJSON {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(tasks)
+	}
+
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(tasks); err != nil {
+		return err
+	}
+	return enc.Close()
+}
+
+// writeCommands writes the commands gt would run for args when passing them
+// through to task, one per line, as shell commands run from the current
+// directory with the variables set by --env and the env config option
+func writeCommands(w io.Writer, args []string) error {
+	dir, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+
+	each := [][]string{args}
+	if names, shared, ok := splitEachTask(args); ok {
+		each = eachTaskArgs(names, shared)
+	}
+
+	var env []string
+	for _, v := range extraEnv() {
+		key, value, _ := strings.Cut(v, "=")
+		env = append(env, key+"="+shellQuote(value))
+	}
+	for _, args := range each {
+		cmd := passthroughCommand(args)
+		words := append([]string{cmd.Path}, cmd.Args[1:]...)
+		for i, word := range words {
+			words[i] = shellQuote(word)
+		}
+		fmt.Fprintf(w, "cd %s && %s\n", shellQuote(dir), strings.Join(append(env, words...), " "))
+	}
+	return nil
+}
+
+// shellSafe matches words that need no quoting in a POSIX shell
+var shellSafe = regexp.MustCompile(`^[A-Za-z0-9_@%+=:,./-]+$`)
+
+// shellQuote quotes s for a POSIX shell when needed
+func shellQuote(s string) string {
+	if shellSafe.MatchString(s) {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
@@ -0,0 +1,182 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"gopkg.in/yaml.v3"
+)
+
+// duplicateTask appends a copy of the task's definition to the Taskfile it
+// is defined in, under newName. The lines of the definition are copied as
+// they are written and added after the last task, so the rest of the file,
+// comments and formatting included, is left alone.
+func duplicateTask(task Task, newName string) error {
+	info, err := os.Stat(task.Source)
+	if err != nil {
+		return err
+	}
+	data, err := os.ReadFile(task.Source)
+	if err != nil {
+		return err
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return newTaskfileError(task.Source, data, err)
+	}
+	node := tasksNode(&doc)
+	if node == nil {
+		return errors.New("the Taskfile has no tasks section")
+	}
+	if node.Style&yaml.FlowStyle != 0 {
+		return errors.New("can't copy tasks written in flow style, like tasks: {...}")
+	}
+
+	// Included tasks are named with their namespace, so find the definition
+	// by the line it starts on
+	found := -1
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == newName {
+			return fmt.Errorf("task %q already exists", newName)
+		}
+		if node.Content[i].Line == task.Line {
+			found = i
+		}
+	}
+	if found < 0 {
+		return fmt.Errorf("can't find %s in %s", task.Name, task.Source)
+	}
+
+	lines := strings.SplitAfter(string(data), "\n")
+	if last := len(lines) - 1; lines[last] == "" {
+		lines = lines[:last]
+	} else {
+		lines[last] += "\n"
+	}
+	first, last := task.Line-1, taskBlockEnd(&doc, node, found, lines)
+
+	// Rename the copy where its key is written, quoted when the name
+	// would read as other YAML
+	key := node.Content[found]
+	from := key.Column - 1
+	to := from + len(key.Value)
+	if key.Style&(yaml.DoubleQuotedStyle|yaml.SingleQuotedStyle) != 0 {
+		to += 2
+	}
+	quoted, err := yaml.Marshal(newName)
+	if err != nil {
+		return err
+	}
+	newKey := strings.TrimSuffix(string(quoted), "\n")
+	block := append([]string{lines[first][:from] + newKey + lines[first][to:]}, lines[first+1:last]...)
+
+	// Leave a blank line before the copy when the last task is spaced out
+	// from the one before it, above its comments
+	lastKey := node.Content[len(node.Content)-2]
+	above := lastKey.Line - 2
+	for above > 0 && strings.HasPrefix(strings.TrimSpace(lines[above]), "#") {
+		above--
+	}
+	if above > 0 && strings.TrimSpace(lines[above]) == "" {
+		block = append([]string{"\n"}, block...)
+	}
+
+	end := taskBlockEnd(&doc, node, len(node.Content)-2, lines)
+	updated := slices.Concat(lines[:end], block, lines[end:])
+	return os.WriteFile(task.Source, []byte(strings.Join(updated, "")), info.Mode().Perm())
+}
+
+// taskBlockEnd returns the index in lines just past the definition of the
+// task whose key is at index i of the tasks mapping node. Blank lines and
+// comments trailing the definition belong with what follows it.
+func taskBlockEnd(doc, node *yaml.Node, i int, lines []string) int {
+	end := len(lines)
+	if i+2 < len(node.Content) {
+		end = node.Content[i+2].Line - 1
+	} else {
+		// The last task ends where the next top-level key starts
+		root := doc.Content[0]
+		for j := 0; j+1 < len(root.Content); j += 2 {
+			if root.Content[j+1] == node && j+2 < len(root.Content) {
+				end = root.Content[j+2].Line - 1
+			}
+		}
+	}
+	for end > node.Content[i].Line {
+		line := strings.TrimSpace(lines[end-1])
+		if line != "" && !strings.HasPrefix(line, "#") {
+			break
+		}
+		end--
+	}
+	return end
+}
+
+// startCopy asks for the name of a copy of the selected task
+func (m model) startCopy() (tea.Model, tea.Cmd) {
+	if safeMode {
+		m.status = "editing disabled in safe mode"
+		return m, nil
+	}
+	task, ok := m.list.SelectedItem().(Task)
+	if !ok || len(m.filteredList) == 0 {
+		return m, nil
+	}
+
+	// Suggest a name next to the original, without its namespace
+	name := task.Name
+	if ns := taskNamespace(task.Name); ns != "" {
+		name = task.Name[len(ns)+1:]
+	}
+
+	m.copying = &task
+	m.copyName = textinput.New()
+	m.copyName.SetValue(name + "-copy")
+	m.copyName.CharLimit = 100
+	m.copyName.Focus()
+	return m, textinput.Blink
+}
+
+// updateCopy handles keys while the name of a copied task is being entered
+func (m model) updateCopy(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+	case "esc":
+		m.copying = nil
+		return m, nil
+	case "enter":
+		task, name := *m.copying, m.copyName.Value()
+		m.copying = nil
+		if name == "" {
+			return m, nil
+		}
+		if err := duplicateTask(task, name); err != nil {
+			m.status = fmt.Sprintf("✗ copy failed: %v", err)
+			return m, nil
+		}
+		m.status = fmt.Sprintf("copied %s to %s in %s", task.Name, name, task.Source)
+		return m.reload()
+	}
+
+	var cmd tea.Cmd
+	m.copyName, cmd = m.copyName.Update(msg)
+	return m, cmd
+}
+
+// reload parses the Taskfile again in the background, or asks task for
+// the tasks again when the list comes from task
+func (m model) reload() (tea.Model, tea.Cmd) {
+	load := loadTasks
+	if m.fromTask {
+		load = loadListedTasks
+	}
+	m.loading = true
+	return m, tea.Batch(m.spinner.Tick, load)
+}
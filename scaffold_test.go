@@ -0,0 +1,102 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestDuplicateTask(t *testing.T) {
+	const taskfile = `version: '3'
+
+# Shared settings
+vars:
+  OUT: "bin/app"
+
+tasks:
+  # Build the binary
+  build:
+    desc: Build it
+    cmds:
+      - go build -o {{.OUT}} .  # keep the output path in one place
+
+  "lint":
+    cmds: [golangci-lint run]
+
+  # Run last
+  release: goreleaser
+
+output: prefixed
+`
+	const want = `version: '3'
+
+# Shared settings
+vars:
+  OUT: "bin/app"
+
+tasks:
+  # Build the binary
+  build:
+    desc: Build it
+    cmds:
+      - go build -o {{.OUT}} .  # keep the output path in one place
+
+  "lint":
+    cmds: [golangci-lint run]
+
+  # Run last
+  release: goreleaser
+
+  build-debug:
+    desc: Build it
+    cmds:
+      - go build -o {{.OUT}} .  # keep the output path in one place
+
+  lint-fix:
+    cmds: [golangci-lint run]
+
+  'release: dry #1': goreleaser
+
+  '*release': goreleaser
+
+output: prefixed
+`
+	path := filepath.Join(t.TempDir(), "Taskfile.yml")
+	if err := os.WriteFile(path, []byte(taskfile), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	tasks, err := parseTaskfileAt(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, copy := range []struct{ name, newName string }{
+		{"build", "build-debug"}, {"lint", "lint-fix"}, {"release", "release: dry #1"}, {"release", "*release"},
+	} {
+		for _, task := range tasks {
+			if task.Name == copy.name {
+				if err := duplicateTask(task, copy.newName); err != nil {
+					t.Fatalf("duplicateTask(%s) = %v", copy.name, err)
+				}
+			}
+		}
+	}
+
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != want {
+		t.Errorf("Taskfile after copying:\n%s\nwant:\n%s", got, want)
+	}
+	copied, err := parseTaskfileAt(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if names := taskNames(copied); !slices.Contains(names, "release: dry #1") || !slices.Contains(names, "*release") {
+		t.Errorf("tasks after copying are %q, want the quoted names", names)
+	}
+	if err := duplicateTask(tasks[0], "release"); err == nil {
+		t.Error("duplicateTask accepted the name of an existing task")
+	}
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// shellCommand builds the command that runs line with the system shell
+func shellCommand(line string) *exec.Cmd {
+	if runtime.GOOS == "windows" {
+		return exec.Command("cmd", "/C", line)
+	}
+	return exec.Command("sh", "-c", line)
+}
+
+// taskfileDir returns the directory task runs the tasks in: the one of the
+// first Taskfile given with --taskfile, or of the Taskfile found
+func taskfileDir() string {
+	if len(taskfiles) > 0 {
+		if abs, err := filepath.Abs(taskfiles[0]); err == nil {
+			return filepath.Dir(abs)
+		}
+	}
+	if path, err := findTaskfile(); err == nil {
+		return filepath.Dir(path)
+	}
+	dir, _ := os.Getwd()
+	return dir
+}
+
+// startScratch asks for an ad-hoc command, if the scratch config option
+// allows them
+func (m model) startScratch() (tea.Model, tea.Cmd) {
+	if !config.Scratch {
+		m.status = "ad-hoc commands are off, set scratch: true in the config to use them"
+		return m, nil
+	}
+	if safeMode {
+		return m.refuseInSafeMode()
+	}
+
+	m.scratching = true
+	m.scratchCmd = textinput.New()
+	m.scratchCmd.CharLimit = 1000
+	m.scratchCmd.Focus()
+	return m, textinput.Blink
+}
+
+// updateScratch handles keys while an ad-hoc command is being entered
+func (m model) updateScratch(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+	case "esc":
+		m.scratching = false
+		return m, nil
+	case "enter":
+		m.scratching = false
+		if line := m.scratchCmd.Value(); line != "" {
+			return m.runScratch(line)
+		}
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.scratchCmd, cmd = m.scratchCmd.Update(msg)
+	return m, cmd
+}
+
+// runScratch runs an ad-hoc command with the shell in the Taskfile's
+// directory and environment, with its output shown like a task's
+func (m model) runScratch(line string) (tea.Model, tea.Cmd) {
+	cmd := shellCommand(line)
+	cmd.Dir = taskfileDir()
+	cmd.Env = append(os.Environ(), extraEnv()...)
+
+	run, err := startCapturedCommand("$ "+line, cmd)
+	if err != nil {
+		m.status = fmt.Sprintf("✗ %s failed to start: %v", line, err)
+		return m, nil
+	}
+	run.scratch = line
+
+	m.run = run
+	m.status = ""
+	m.output = newOutputViewport(m.width, m.height)
+	return m, waitForRunEvent(run)
+}
@@ -0,0 +1,267 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"slices"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// servePollInterval is how often gt serve checks the Taskfiles for changes
+const servePollInterval = time.Second
+
+// serveSocket is the path of the socket gt serve listens on
+var serveSocket string
+
+// serveCmd keeps the tasks parsed in memory and answers queries over a
+// unix socket, so editor plugins don't have to start gt for each query
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Answer task list queries over a unix socket",
+	Long: `Parse the Taskfile once and answer task list queries over a unix socket,
+re-parsing it whenever one of the Taskfiles changes.
+
+Clients send one JSON request per line and get one JSON response per line:
+
+  {"method": "list"}   responds with {"tasks": [...]}
+  {"method": "watch"}  responds with the tasks now and again on every change
+
+When the Taskfile can't be parsed, responses have an "error" instead of
+tasks. The socket defaults to a path in the temporary directory derived
+from the Taskfile's directory, and is printed on startup.`,
+	Example: `  gt serve
+  gt serve --socket /tmp/gt.sock`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		mustInitialize()
+
+		root, err := findTaskfile()
+		if err != nil {
+			reportInitError(err)
+			os.Exit(initExitCode(err))
+		}
+		socket := serveSocket
+		if socket == "" {
+			socket = defaultSocket(filepath.Dir(root))
+		}
+
+		if err := serveTasks(socket, root); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+// serveRequest is a query sent by a client
+type serveRequest struct {
+	Method string `json:"method"`
+}
+
+// serveResponse answers a query, or reports a change to watchers
+type serveResponse struct {
+	Tasks []Task `json:"tasks,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+// taskServer holds the parsed tasks shared by all connections
+type taskServer struct {
+	mu       sync.Mutex
+	current  serveResponse
+	watchers map[chan serveResponse]bool
+}
+
+// defaultSocket returns the socket path used for the Taskfile in dir, so
+// each project gets its own server
+func defaultSocket(dir string) string {
+	sum := sha256.Sum256([]byte(dir))
+	return filepath.Join(os.TempDir(), fmt.Sprintf("gt-%x.sock", sum[:6]))
+}
+
+// serveTasks listens on socket until interrupted, reloading the tasks when
+// the Taskfile at root or any Taskfile the tasks came from changes
+func serveTasks(socket, root string) error {
+	// Don't start twice, but take over a socket left behind by a server
+	// that didn't shut down
+	if conn, err := net.Dial("unix", socket); err == nil {
+		conn.Close()
+		return fmt.Errorf("%s is already being served", socket)
+	}
+	os.Remove(socket)
+
+	ln, err := net.Listen("unix", socket)
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		ln.Close()
+	}()
+
+	s := &taskServer{current: serveResponse{Tasks: tasks}, watchers: map[chan serveResponse]bool{}}
+	go s.watchTaskfiles(ctx, root)
+
+	fmt.Fprintf(os.Stderr, "gt: serving the tasks of %s on %s\n", root, socket)
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+		go s.handle(conn)
+	}
+}
+
+// handle answers the requests of one client until it disconnects. A watch
+// request keeps the connection for updates from then on.
+func (s *taskServer) handle(conn net.Conn) {
+	defer conn.Close()
+	dec := json.NewDecoder(conn)
+	enc := json.NewEncoder(conn)
+
+	for {
+		var req serveRequest
+		if err := dec.Decode(&req); err != nil {
+			return
+		}
+
+		switch req.Method {
+		case "list":
+			if enc.Encode(s.snapshot()) != nil {
+				return
+			}
+		case "watch":
+			s.watch(enc)
+			return
+		default:
+			if enc.Encode(serveResponse{Error: fmt.Sprintf("unknown method %q", req.Method)}) != nil {
+				return
+			}
+		}
+	}
+}
+
+// snapshot returns the current tasks or parse error
+func (s *taskServer) snapshot() serveResponse {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.current
+}
+
+// watch sends the current tasks and then every update, until the client
+// goes away
+func (s *taskServer) watch(enc *json.Encoder) {
+	updates := make(chan serveResponse, 1)
+	s.mu.Lock()
+	s.watchers[updates] = true
+	updates <- s.current
+	s.mu.Unlock()
+
+	defer func() {
+		s.mu.Lock()
+		delete(s.watchers, updates)
+		s.mu.Unlock()
+	}()
+
+	for update := range updates {
+		if enc.Encode(update) != nil {
+			return
+		}
+	}
+}
+
+// publish replaces the current tasks and tells the watchers. A watcher
+// that hasn't caught up only gets the latest update.
+func (s *taskServer) publish(update serveResponse) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.current = update
+	for updates := range s.watchers {
+		select {
+		case <-updates:
+		default:
+		}
+		updates <- update
+	}
+}
+
+// watchTaskfiles polls the Taskfiles for changes and reloads the tasks when
+// any of them is modified, created or removed
+func (s *taskServer) watchTaskfiles(ctx context.Context, root string) {
+	seen := modTimes(watchedFiles(root, tasks))
+	ticker := time.NewTicker(servePollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		// Keep watching files that had tasks before, so fixing a broken
+		// include is noticed too
+		files := append(watchedFiles(root, s.snapshot().Tasks), slices.Collect(maps.Keys(seen))...)
+		now := modTimes(files)
+		if sameModTimes(seen, now) {
+			continue
+		}
+
+		update := serveResponse{}
+		if err := initialize(); err != nil {
+			update.Error = initErrorMessage(err)
+		} else {
+			update.Tasks = tasks
+		}
+		s.publish(update)
+		seen = modTimes(append(watchedFiles(root, update.Tasks), slices.Collect(maps.Keys(now))...))
+	}
+}
+
+// watchedFiles returns the root Taskfile and the Taskfiles tasks came from
+func watchedFiles(root string, tasks []Task) []string {
+	files := []string{root}
+	for _, task := range tasks {
+		files = append(files, task.Source)
+	}
+	return files
+}
+
+// modTimes returns the modification time of each file, zero for files
+// that don't exist
+func modTimes(files []string) map[string]time.Time {
+	times := map[string]time.Time{}
+	for _, file := range files {
+		if info, err := os.Stat(file); err == nil {
+			times[file] = info.ModTime()
+		} else {
+			times[file] = time.Time{}
+		}
+	}
+	return times
+}
+
+// sameModTimes reports whether no file changed between two polls
+func sameModTimes(before, after map[string]time.Time) bool {
+	for file, t := range after {
+		if !before[file].Equal(t) {
+			return false
+		}
+	}
+	return true
+}
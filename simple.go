@@ -0,0 +1,94 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/list"
+)
+
+// simplePicker picks tasks from a numbered list instead of the TUI
+var simplePicker bool
+
+// isTerminal reports whether f is a terminal rather than a pipe or file
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// useSimplePicker reports whether to use the numbered list: when asked for
+// with --simple, or when the TUI can't work because the input or the
+// screen it draws on isn't a terminal
+func useSimplePicker() bool {
+	screen := os.Stdout
+	if printSelection {
+		screen = os.Stderr
+	}
+	return simplePicker || !isTerminal(os.Stdin) || !isTerminal(screen)
+}
+
+// runSimplePicker lists the tasks matching query with numbers on stderr,
+// reads the number of the task to run from stdin and runs it, or prints it
+// with --print-selection. It returns the exit code.
+func runSimplePicker(query string) int {
+	mustInitialize()
+
+	var items []list.Item
+	for _, task := range tasks {
+		items = append(items, task)
+	}
+	matched, _, err := fuzzyFilter(items, query, 0, caseSensitive)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return exitError
+	}
+	if len(matched) == 0 {
+		fmt.Fprintf(os.Stderr, "gt: no tasks match '%s'\n", query)
+		return exitError
+	}
+
+	task, ok := pickNumbered(os.Stdin, os.Stderr, matched)
+	if !ok {
+		return exitNoSelection
+	}
+	if printSelection {
+		fmt.Println(task.Name)
+		return 0
+	}
+	return runTaskDirect([]string{task.Name})
+}
+
+// pickNumbered writes the tasks as a numbered list to w and reads the
+// number of the chosen one from r, asking again after invalid input. It
+// reports false when the input ends or is empty.
+func pickNumbered(r io.Reader, w io.Writer, items []list.Item) (Task, bool) {
+	width := 0
+	for _, item := range items {
+		width = max(width, len(displayName(item.(Task).Name)))
+	}
+	for i, item := range items {
+		task := item.(Task)
+		fmt.Fprintf(w, "%3d) %-*s  %s\n", i+1, width, displayName(task.Name), task.Desc)
+	}
+
+	scanner := bufio.NewScanner(r)
+	for {
+		fmt.Fprintf(w, "Run which task? [1-%d, enter to cancel] ", len(items))
+		if !scanner.Scan() {
+			fmt.Fprintln(w)
+			return Task{}, false
+		}
+		answer := strings.TrimSpace(scanner.Text())
+		if answer == "" {
+			return Task{}, false
+		}
+		if n, err := strconv.Atoi(answer); err == nil && n >= 1 && n <= len(items) {
+			return items[n-1].(Task), true
+		}
+		fmt.Fprintf(w, "%q is not a number from 1 to %d\n", answer, len(items))
+	}
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// tasksFromList turns the tasks 'task --list-all --json' reports into the
+// task list, keeping what gt's parser found about the tasks it knows too
+func tasksFromList(listed []listedTask, parsed []Task) []Task {
+	known := make(map[string]Task, len(parsed))
+	for _, task := range parsed {
+		known[task.Name] = task
+	}
+
+	var list []Task
+	for _, l := range listed {
+		task, ok := known[l.Name]
+		if !ok {
+			task = Task{Name: l.Name}
+			if len(taskfiles) == 1 {
+				task.Taskfile = taskfiles[0]
+			}
+		}
+		task.Info = l.TaskInfo
+		if l.TaskInfo != nil {
+			task.Summary = strings.TrimSpace(l.Summary)
+			if task.Source == "" {
+				task.Source, task.Line = l.Location.Taskfile, l.Location.Line
+			}
+		}
+		task.Desc = preferredDesc(l.Desc, task.Summary)
+		task.Tags = descTags(task.Desc)
+		list = append(list, task)
+	}
+	return list
+}
+
+// loadListedTasks loads the tasks task lists instead of the parsed ones,
+// without blocking the TUI. It falls back to the parsed tasks when task
+// can't list them.
+func loadListedTasks() tea.Msg {
+	msg := loadTasks().(tasksLoadedMsg)
+	if msg.err != nil {
+		return msg
+	}
+	if !taskSupports(minJSONListVersion) {
+		msg.listErr = fmt.Errorf("task %s or newer is needed", minJSONListVersion)
+		return msg
+	}
+	listed, err := listedTasks()
+	if err != nil {
+		msg.listErr = err
+		return msg
+	}
+
+	msg.tasks = tasksFromList(listed, msg.tasks)
+	if !showAll {
+		patterns := append(append([]string{}, config.Exclude...), excludeFlags...)
+		listed := len(msg.tasks)
+		if msg.tasks, err = excludeTasks(msg.tasks, patterns); err != nil {
+			return tasksLoadedMsg{err: err}
+		}
+		if len(msg.tasks) == 0 {
+			return tasksLoadedMsg{err: allExcluded(listed, patterns)}
+		}
+	}
+	sortTasksByName(msg.tasks)
+	tasks = msg.tasks
+	msg.listed = true
+	return msg
+}
+
+// toggleSource swaps the list between the tasks gt's parser found and the
+// ones task lists, reloading them in place
+func (m model) toggleSource() (tea.Model, tea.Cmd) {
+	load := loadListedTasks
+	if m.fromTask {
+		load = loadTasks
+	}
+	m.loading = true
+	return m, tea.Batch(m.spinner.Tick, load)
+}
+
+// sourceName names where the listed tasks come from, for the help line
+func (m model) sourceName() string {
+	if m.fromTask {
+		return "task"
+	}
+	return "parser"
+}
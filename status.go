@@ -0,0 +1,51 @@
+package main
+
+import (
+	"runtime"
+	"sync"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// taskStatusMsg maps taskIDs to whether task reports the tasks as up to date
+type taskStatusMsg map[string]bool
+
+// checkTaskStatus returns a command that asks task which of the given tasks
+// are up to date, running the checks in parallel
+func checkTaskStatus(tasks []Task) tea.Cmd {
+	return func() tea.Msg {
+		return taskStatusMsg(taskStatuses(tasks, runtime.NumCPU()))
+	}
+}
+
+// taskStatuses runs 'task --status' for each task using a pool of workers,
+// returning the results by taskID. A task is up to date when the status
+// call exits successfully.
+func taskStatuses(tasks []Task, workers int) map[string]bool {
+	results := make(map[string]bool, len(tasks))
+	jobs := make(chan Task)
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for range max(workers, 1) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for task := range jobs {
+				upToDate := taskCmd.command(task.Taskfile, "--status", task.Name).Run() == nil
+
+				mu.Lock()
+				results[taskID(task)] = upToDate
+				mu.Unlock()
+			}
+		}()
+	}
+
+	for _, task := range tasks {
+		jobs <- task
+	}
+	close(jobs)
+	wg.Wait()
+
+	return results
+}
@@ -0,0 +1,159 @@
+package main
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// maxScanDepth bounds how many directories deep gt looks for Taskfiles
+// below the project, and how far above the working directory it looks
+// for the project's outermost Taskfile
+const maxScanDepth = 4
+
+// skippedDirs are never searched for Taskfiles, besides hidden directories
+var skippedDirs = map[string]bool{"node_modules": true, "vendor": true}
+
+// taskfilesFoundMsg carries the Taskfiles found below the project
+type taskfilesFoundMsg struct {
+	root  string
+	paths []string
+}
+
+// scanRoot returns the directory searched for Taskfiles: the outermost
+// directory with a Taskfile above the working directory, so the whole
+// monorepo is found from any of its projects, or else the working
+// directory. It looks up to the repository root with stop_at_repo_root,
+// and never more than maxScanDepth directories up, so a Taskfile in a
+// home directory doesn't make gt scan all of it.
+func scanRoot() string {
+	cwd, _ := os.Getwd()
+	root := cwd
+	for dir, depth := cwd, 0; ; dir, depth = filepath.Dir(dir), depth+1 {
+		if _, ok := hasTaskfile(dir); ok {
+			root = dir
+		}
+		if filepath.Dir(dir) == dir || depth == maxScanDepth || config.StopAtRepoRoot && isRepoRoot(dir) {
+			return root
+		}
+	}
+}
+
+// hasTaskfile returns the path of the Taskfile in dir, if there is one
+func hasTaskfile(dir string) (string, bool) {
+	for _, name := range searchedTaskfileNames {
+		if _, err := os.Stat(filepath.Join(dir, name)); err == nil {
+			return filepath.Join(dir, name), true
+		}
+	}
+	return "", false
+}
+
+// scanTaskfiles looks for Taskfiles below the project in the background
+func scanTaskfiles() tea.Msg {
+	root := scanRoot()
+	return taskfilesFoundMsg{root: root, paths: findTaskfiles(root)}
+}
+
+// findTaskfiles returns the Taskfile of root and of each directory below
+// it, up to maxScanDepth deep. Directories that can't be read are skipped.
+func findTaskfiles(root string) []string {
+	var paths []string
+	filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || !d.IsDir() {
+			return nil
+		}
+		if path != root {
+			name := d.Name()
+			if strings.HasPrefix(name, ".") || skippedDirs[name] {
+				return filepath.SkipDir
+			}
+			if rel, _ := filepath.Rel(root, path); strings.Count(rel, string(filepath.Separator)) >= maxScanDepth {
+				return filepath.SkipDir
+			}
+		}
+
+		if taskfile, ok := hasTaskfile(path); ok {
+			paths = append(paths, taskfile)
+		}
+		return nil
+	})
+	return paths
+}
+
+// taskfilesFound opens the Taskfile picker, unless the project has only
+// the one Taskfile
+func (m model) taskfilesFound(msg taskfilesFoundMsg) (tea.Model, tea.Cmd) {
+	if len(msg.paths) < 2 {
+		m.status = "no other Taskfiles found below " + msg.root
+		return m, nil
+	}
+	m.switchRoot, m.switching, m.switchIndex = msg.root, msg.paths, 0
+	m.status = ""
+	return m, nil
+}
+
+// updateSwitch handles keys while the Taskfile picker is shown
+func (m model) updateSwitch(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+	case "esc", "q":
+		m.switching = nil
+	case "up", "k":
+		m.switchIndex = max(m.switchIndex-1, 0)
+	case "down", "j":
+		m.switchIndex = min(m.switchIndex+1, len(m.switching)-1)
+	case "enter":
+		return m.switchTaskfile(m.switching[m.switchIndex])
+	}
+	return m, nil
+}
+
+// switchTaskfile reloads the list with the tasks of the Taskfile at path,
+// which tasks are then run with
+func (m model) switchTaskfile(path string) (tea.Model, tea.Cmd) {
+	taskfiles = []string{path}
+	m.switching = nil
+	m.marked = nil
+	m.pinned = nil
+	m.baseline, m.changed = nil, nil
+	m.status = "Taskfile: " + m.relativeTaskfile(path)
+	return m.reload()
+}
+
+// relativeTaskfile returns path relative to the directory that was scanned
+func (m model) relativeTaskfile(path string) string {
+	if rel, err := filepath.Rel(m.switchRoot, path); err == nil {
+		return rel
+	}
+	return path
+}
+
+// viewSwitch renders the Taskfile picker in place of the task list
+func (m model) viewSwitch() string {
+	current := ""
+	if len(taskfiles) > 0 {
+		current, _ = filepath.Abs(taskfiles[0])
+	} else if found, err := findTaskfile(); err == nil {
+		current = found
+	}
+
+	var b strings.Builder
+	b.WriteString(m.currentTheme().mutedStyle().Bold(true).Render("Switch to the Taskfile in:") + "\n")
+	for i, path := range m.switching {
+		line := "  " + m.relativeTaskfile(path)
+		if path == current {
+			line += " (current)"
+		}
+		if i == m.switchIndex {
+			b.WriteString(m.currentTheme().selectedStyle().Render("> "+line[2:]) + "\n")
+		} else {
+			b.WriteString(m.currentTheme().normalStyle().Render(line) + "\n")
+		}
+	}
+	return b.String()
+}
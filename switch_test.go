@@ -0,0 +1,34 @@
+package main
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestScanRoot(t *testing.T) {
+	taskfile := "version: '3'\n"
+	dir := inProject(t, map[string]string{
+		"Taskfile.yml":                 taskfile,
+		"a/Taskfile.yml":               taskfile,
+		"a/b/c/d/e/Taskfile.yml":       taskfile,
+		"a/b/c/d/e/f/g/Taskfile.yml":   taskfile,
+		"a/b/c/d/e/f/g/h/.placeholder": "",
+	})
+	deep := filepath.Join(dir, "a", "b", "c", "d", "e", "f", "g", "h")
+
+	t.Chdir(filepath.Join(dir, "a", "b"))
+	if got := scanRoot(); got != dir {
+		t.Errorf("scanRoot() = %s, want the repository root %s", got, dir)
+	}
+
+	// Without stopping at the repository root, the search still only goes
+	// maxScanDepth directories up
+	config.StopAtRepoRoot = false
+	defer func() { config.StopAtRepoRoot = true }()
+	t.Chdir(deep)
+	want := filepath.Join(dir, "a", "b", "c", "d", "e")
+	if got := scanRoot(); got != want {
+		t.Errorf("scanRoot() from %s = %s, want %s", strings.TrimPrefix(deep, dir), got, want)
+	}
+}
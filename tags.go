@@ -0,0 +1,78 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/list"
+)
+
+// defaultTagPattern finds tags written as [name] in task descriptions
+const defaultTagPattern = `\[([\w.-]+)\]`
+
+// tagPattern is the compiled tag_pattern config option
+var tagPattern = regexp.MustCompile(defaultTagPattern)
+
+// setTagPattern compiles the tag pattern of the config, which must have a
+// group capturing the tag name
+func setTagPattern(pattern string) error {
+	if pattern == "" {
+		return nil
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return fmt.Errorf("tag_pattern: %w", err)
+	}
+	if re.NumSubexp() < 1 {
+		return fmt.Errorf("tag_pattern %q has no group capturing the tag", pattern)
+	}
+	tagPattern = re
+	return nil
+}
+
+// descTags returns the tags found in a task description, in order and
+// without duplicates
+func descTags(desc string) []string {
+	var tags []string
+	for _, match := range tagPattern.FindAllStringSubmatch(desc, -1) {
+		if tag := strings.ToLower(match[1]); tag != "" && !slices.Contains(tags, tag) {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+// tagScope keeps the items with a tag starting with each #tag word of the
+// filter, so tags match while they are being typed, and returns them with
+// the rest of the filter
+func tagScope(items []list.Item, filter string) ([]list.Item, string) {
+	var wanted, rest []string
+	for _, word := range strings.Fields(filter) {
+		if tag, ok := strings.CutPrefix(word, "#"); ok {
+			wanted = append(wanted, strings.ToLower(tag))
+		} else {
+			rest = append(rest, word)
+		}
+	}
+	if len(wanted) == 0 {
+		return items, filter
+	}
+
+	var scoped []list.Item
+	for _, item := range items {
+		task := item.(Task)
+		if !slices.ContainsFunc(wanted, func(prefix string) bool { return !hasTag(task, prefix) }) {
+			scoped = append(scoped, item)
+		}
+	}
+	return scoped, strings.Join(rest, " ")
+}
+
+// hasTag reports whether one of the task's tags starts with prefix
+func hasTag(task Task, prefix string) bool {
+	return slices.ContainsFunc(task.Tags, func(tag string) bool {
+		return strings.HasPrefix(tag, prefix)
+	})
+}
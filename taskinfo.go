@@ -0,0 +1,113 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// TaskInfo is what 'task --list-all --json' reports about a task, which
+// is more accurate than what gt's parser finds
+type TaskInfo struct {
+	Summary  string   `json:"summary,omitempty" yaml:"summary,omitempty"`
+	Aliases  []string `json:"aliases,omitempty" yaml:"aliases,omitempty"`
+	UpToDate *bool    `json:"up_to_date,omitempty" yaml:"up_to_date,omitempty"`
+	Location struct {
+		Taskfile string `json:"taskfile" yaml:"taskfile"`
+		Line     int    `json:"line" yaml:"line"`
+		Column   int    `json:"column" yaml:"column"`
+	} `json:"location" yaml:"location"`
+}
+
+// taskInfoMsg maps taskIDs to what task reports about the tasks
+type taskInfoMsg map[string]*TaskInfo
+
+// checkTaskInfo returns a command that asks task for the metadata of the
+// tasks, listing each of their Taskfiles, or nil when the task version
+// can't list tasks as JSON
+func checkTaskInfo(tasks []Task) tea.Cmd {
+	if !taskSupports(minJSONListVersion) {
+		return nil
+	}
+	var taskfiles []string
+	for _, task := range tasks {
+		if !slices.Contains(taskfiles, task.Taskfile) {
+			taskfiles = append(taskfiles, task.Taskfile)
+		}
+	}
+	return func() tea.Msg {
+		infos := make(taskInfoMsg)
+		for _, taskfile := range taskfiles {
+			listed, err := listedTasksIn(taskfile)
+			if err != nil {
+				debugf("task --list-all --json: %v", err)
+				continue
+			}
+			for _, task := range listed {
+				infos[taskID(Task{Name: task.Name, Taskfile: taskfile})] = task.TaskInfo
+			}
+		}
+		return infos
+	}
+}
+
+// askTaskInfo asks task for the tasks' metadata once the details view or
+// the pinned preview shows it, the first time since the tasks were loaded.
+// Listing the tasks runs their status checks to report whether they are
+// up to date, which is too slow to do on every load.
+func (m *model) askTaskInfo() tea.Cmd {
+	// Tasks listed by task already have it
+	if m.infoAsked || m.fromTask || !m.expanded && m.pinned == nil {
+		return nil
+	}
+	m.infoAsked = true
+	var all []Task
+	for _, item := range m.allItems {
+		all = append(all, item.(Task))
+	}
+	return checkTaskInfo(all)
+}
+
+// taskInfoLoaded stores the metadata task reported on the listed tasks
+func (m model) taskInfoLoaded(msg taskInfoMsg) (tea.Model, tea.Cmd) {
+	for i, item := range m.allItems {
+		task := item.(Task)
+		task.Info = msg[taskID(task)]
+		m.allItems[i] = task
+	}
+	if m.pinned != nil {
+		m.pinned.Info = msg[taskID(*m.pinned)]
+	}
+
+	// Keep the selection while the list is rebuilt
+	selected := m.list.Index()
+	m.applyFilter()
+	m.list.Select(selected)
+	return m, nil
+}
+
+// infoDetails renders what task reports about the task, or the location
+// gt's parser found when task didn't report on it
+func infoDetails(task Task) string {
+	info := task.Info
+	if info == nil {
+		if task.Source == "" {
+			return ""
+		}
+		return fmt.Sprintf("\n    location: %s:%d", taskfileTag(task.Source), task.Line)
+	}
+
+	details := fmt.Sprintf("\n    location: %s:%d:%d", taskfileTag(info.Location.Taskfile), info.Location.Line, info.Location.Column)
+	if info.UpToDate != nil {
+		details += fmt.Sprintf("\n    up to date: %t", *info.UpToDate)
+	}
+	if len(info.Aliases) > 0 {
+		details += "\n    aliases: " + strings.Join(info.Aliases, ", ")
+	}
+	if info.Summary != "" {
+		details += summaryDetails(info.Summary)
+	}
+	return details
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"sync"
+)
+
+// version is gt's own version, set at build time with
+// -ldflags "-X main.version=..."
+var version = "dev"
+
+// semver is a task release version
+type semver struct {
+	Major, Minor, Patch int
+}
+
+func (v semver) String() string {
+	return fmt.Sprintf("v%d.%d.%d", v.Major, v.Minor, v.Patch)
+}
+
+// atLeast reports whether v is the same as or newer than min
+func (v semver) atLeast(min semver) bool {
+	if v.Major != min.Major {
+		return v.Major > min.Major
+	}
+	if v.Minor != min.Minor {
+		return v.Minor > min.Minor
+	}
+	return v.Patch >= min.Patch
+}
+
+// Minimum task versions of the features gt uses when they are available
+var (
+	// minJSONListVersion supports 'task --list-all --json'
+	minJSONListVersion = semver{3, 10, 0}
+)
+
+// semverPattern finds a version like v3.43.3 in task's --version output
+var semverPattern = regexp.MustCompile(`v?(\d+)\.(\d+)\.(\d+)`)
+
+// parseSemver extracts the first version number in s
+func parseSemver(s string) (semver, bool) {
+	match := semverPattern.FindStringSubmatch(s)
+	if match == nil {
+		return semver{}, false
+	}
+	major, _ := strconv.Atoi(match[1])
+	minor, _ := strconv.Atoi(match[2])
+	patch, _ := strconv.Atoi(match[3])
+	return semver{major, minor, patch}, true
+}
+
+var (
+	taskVersionOnce  sync.Once
+	taskVersionValue semver
+	taskVersionKnown bool
+)
+
+// taskVersion returns the version of the task binary in use, asking it only
+// once and only when a feature depending on it is used, since running task
+// slows down startup. It reports false when the version couldn't be
+// determined.
+func taskVersion() (semver, bool) {
+	taskVersionOnce.Do(func() {
+		taskVersionValue, taskVersionKnown = parseSemver(taskCmd.version())
+		if taskVersionKnown {
+			debugf("task version: %s", taskVersionValue)
+		} else {
+			debugf("task version: unknown")
+		}
+	})
+	return taskVersionValue, taskVersionKnown
+}
+
+// taskSupports reports whether the task binary is at least version min.
+// Features are left off when the version is unknown.
+func taskSupports(min semver) bool {
+	v, ok := taskVersion()
+	return ok && v.atLeast(min)
+}
+
+// showVersion prints the versions of gt and task
+var showVersion bool
+
+// printVersion prints gt's version and the detected task version
+func printVersion() {
+	fmt.Printf("gt %s\n", version)
+
+	var err error
+	if taskCmd, err = findTaskCommand(); err != nil {
+		fmt.Println("task: not found")
+		os.Exit(exitNoTask)
+	}
+	if v, ok := taskVersion(); ok {
+		fmt.Printf("task %s\n", v)
+	} else {
+		fmt.Println("task: unknown version")
+	}
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"fmt"
+	"path"
+
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// theme is a named set of colors used to render the TUI
+type theme struct {
+	name     string
+	selected lipgloss.Color // Selected task and titles
+	normal   lipgloss.Color // Other tasks
+	muted    lipgloss.Color // Group headers, source tags and descriptions
+}
+
+// themes are the presets selectable with the theme config option or ctrl+t,
+// in the order ctrl+t cycles through them
+var themes = []theme{
+	{name: "dark", selected: "170", normal: "252", muted: "240"},
+	{name: "light", selected: "127", normal: "235", muted: "245"},
+	{name: "high-contrast", selected: "226", normal: "231", muted: "250"},
+}
+
+// findTheme returns the index of the preset with the given name
+func findTheme(name string) (int, bool) {
+	for i, t := range themes {
+		if t.name == name {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// colorNames are the basic colors color rules can use by name
+var colorNames = map[string]lipgloss.Color{
+	"black":   "0",
+	"red":     "1",
+	"green":   "2",
+	"yellow":  "3",
+	"blue":    "4",
+	"magenta": "5",
+	"cyan":    "6",
+	"white":   "7",
+}
+
+// taskColor returns the color of the first color rule matching the task
+// name, if any
+func taskColor(name string) (lipgloss.Color, bool) {
+	for _, rule := range config.Colors {
+		if match, _ := path.Match(rule.Pattern, name); match {
+			if color, ok := colorNames[rule.Color]; ok {
+				return color, true
+			}
+			return lipgloss.Color(rule.Color), true
+		}
+	}
+	return "", false
+}
+
+// checkColorRules reports the first color rule with an invalid pattern
+func checkColorRules(rules []ColorRule) error {
+	for _, rule := range rules {
+		if _, err := path.Match(rule.Pattern, ""); err != nil {
+			return fmt.Errorf("colors: pattern %q: %w", rule.Pattern, err)
+		}
+	}
+	return nil
+}
+
+// selectedStyle renders the selected task
+func (t theme) selectedStyle() lipgloss.Style {
+	return lipgloss.NewStyle().Foreground(t.selected).Bold(true)
+}
+
+// normalStyle renders tasks that aren't selected
+func (t theme) normalStyle() lipgloss.Style {
+	return lipgloss.NewStyle().Foreground(t.normal)
+}
+
+// mutedStyle renders secondary text such as group headers
+func (t theme) mutedStyle() lipgloss.Style {
+	return lipgloss.NewStyle().Foreground(t.muted)
+}
+
+// newDelegate creates the list delegate styled with the theme. Items take
+// two lines when descriptions are shown, so the list's page keys move by
+// about as many tasks as viewList shows.
+func newDelegate(t theme, showDescription bool, d density) list.DefaultDelegate {
+	delegate := list.NewDefaultDelegate()
+	delegate.Styles.SelectedTitle = delegate.Styles.SelectedTitle.Foreground(t.selected)
+	delegate.Styles.SelectedDesc = delegate.Styles.SelectedDesc.Foreground(t.muted)
+	delegate.Styles.NormalTitle = delegate.Styles.NormalTitle.Foreground(t.normal)
+
+	// Reduce spacing between items to the minimum the density allows
+	delegate.SetSpacing(d.spacing())
+	delegate.ShowDescription = showDescription
+	delegate.Styles.NormalTitle = delegate.Styles.NormalTitle.PaddingTop(0).PaddingBottom(0).MarginTop(0).MarginBottom(0)
+	delegate.Styles.NormalDesc = delegate.Styles.NormalDesc.PaddingTop(0).PaddingBottom(0).MarginTop(0).MarginBottom(0)
+	delegate.Styles.SelectedTitle = delegate.Styles.SelectedTitle.PaddingTop(0).PaddingBottom(0).MarginTop(0).MarginBottom(0)
+	delegate.Styles.SelectedDesc = delegate.Styles.SelectedDesc.PaddingTop(0).PaddingBottom(0).MarginTop(0).MarginBottom(0)
+
+	return delegate
+}
+
+// currentTheme returns the active theme preset
+func (m model) currentTheme() theme {
+	return themes[m.theme]
+}
+
+// cycleTheme switches to the next theme preset
+func (m *model) cycleTheme() {
+	m.theme = (m.theme + 1) % len(themes)
+	m.list.SetDelegate(newDelegate(m.currentTheme(), m.allDescs, m.density))
+	m.status = "theme: " + m.currentTheme().name
+}
@@ -0,0 +1,155 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// viewName is the saved view to open the TUI with, given with --view
+var viewName string
+
+// savedView is a filter saved under a name with w, along with the toggles
+// that narrow the list
+type savedView struct {
+	Filter     string `json:"filter"`
+	Documented bool   `json:"documented,omitempty"`
+	Group      string `json:"group,omitempty"`
+	MatchCase  bool   `json:"match_case,omitempty"`
+}
+
+// viewsPath returns the location of the saved views
+func viewsPath() (string, error) {
+	dir, err := configDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "views.json"), nil
+}
+
+// loadViews reads the saved views. Having none saved yet is not an error.
+func loadViews() (map[string]savedView, error) {
+	views := map[string]savedView{}
+	path, err := viewsPath()
+	if err != nil {
+		return views, err
+	}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return views, nil
+	}
+	if err != nil {
+		return views, err
+	}
+	if err := json.Unmarshal(data, &views); err != nil {
+		return views, fmt.Errorf("%s: %w", path, err)
+	}
+	return views, nil
+}
+
+// saveView stores view under name, replacing any view with that name
+func saveView(name string, view savedView) error {
+	views, err := loadViews()
+	if err != nil {
+		return err
+	}
+	views[name] = view
+
+	path, err := viewsPath()
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(views, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// findView returns the view saved under name
+func findView(name string) (savedView, error) {
+	views, err := loadViews()
+	if err != nil {
+		return savedView{}, err
+	}
+	if view, ok := views[name]; ok {
+		return view, nil
+	}
+
+	names := slices.Sorted(maps.Keys(views))
+	if len(names) == 0 {
+		return savedView{}, fmt.Errorf("no view %q, none are saved yet; press w in the TUI to save one", name)
+	}
+	return savedView{}, fmt.Errorf("no view %q, saved views: %s", name, strings.Join(names, ", "))
+}
+
+// currentView captures the filter and toggles shown now
+func (m model) currentView() savedView {
+	return savedView{
+		Filter:     m.filter.Value(),
+		Documented: m.documented,
+		Group:      m.grouping.String(),
+		MatchCase:  m.matchCase,
+	}
+}
+
+// applyView restores the filter and toggles of a saved view
+func (m *model) applyView(view savedView) {
+	m.filter.SetValue(view.Filter)
+	m.filter.CursorEnd()
+	m.documented = view.Documented
+	m.matchCase = view.MatchCase
+	m.grouping = groupFlat
+	for g := groupFlat; g <= groupByFile; g++ {
+		if g.String() == view.Group {
+			m.grouping = g
+		}
+	}
+	m.applyFilter()
+}
+
+// startSaveView asks for the name to save the current filter under
+func (m model) startSaveView() (tea.Model, tea.Cmd) {
+	m.savingView = true
+	m.viewInput = textinput.New()
+	m.viewInput.CharLimit = 100
+	m.viewInput.Focus()
+	return m, textinput.Blink
+}
+
+// updateSaveView handles keys while the name of a view is being entered
+func (m model) updateSaveView(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+	case "esc":
+		m.savingView = false
+		return m, nil
+	case "enter":
+		name := strings.TrimSpace(m.viewInput.Value())
+		if name == "" {
+			return m, nil
+		}
+		m.savingView = false
+		if err := saveView(name, m.currentView()); err != nil {
+			m.status = "✗ saving the view failed: " + err.Error()
+		} else {
+			m.status = "saved view " + name + ", open it with gt --view " + name
+		}
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.viewInput, cmd = m.viewInput.Update(msg)
+	return m, cmd
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"maps"
+	"slices"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// taskfilePollInterval is how often the TUI checks the Taskfiles for changes
+const taskfilePollInterval = 2 * time.Second
+
+// fileTimes maps files to their modification times
+type fileTimes map[string]time.Time
+
+// taskfilesPolledMsg carries the modification times of the Taskfiles the
+// tasks were loaded from
+type taskfilesPolledMsg fileTimes
+
+// sessionTaskfiles returns the Taskfiles the tasks were loaded from: the
+// ones given with --taskfile or found, and the ones the tasks came from
+func sessionTaskfiles(tasks []Task) []string {
+	files := slices.Clone(taskfiles)
+	if len(files) == 0 {
+		if path, err := findTaskfile(); err == nil {
+			files = []string{path}
+		}
+	}
+	for _, task := range tasks {
+		files = append(files, task.Source)
+	}
+	return files
+}
+
+// pollTaskfiles checks the modification times of files after a while
+func pollTaskfiles(files []string) tea.Cmd {
+	return tea.Tick(taskfilePollInterval, func(time.Time) tea.Msg {
+		return taskfilesPolledMsg(modTimes(files))
+	})
+}
+
+// taskfilesPolled notes when a Taskfile changed since the tasks were
+// loaded, so the details shown may be out of date, and keeps polling
+func (m model) taskfilesPolled(msg taskfilesPolledMsg) (tea.Model, tea.Cmd) {
+	// Only compare the files still in use, as a poll may have started
+	// before switching to another Taskfile
+	for file, t := range msg {
+		if loaded, ok := m.mtimes[file]; ok && !loaded.Equal(t) {
+			m.outdated = true
+		}
+	}
+	return m, pollTaskfiles(slices.Collect(maps.Keys(m.mtimes)))
+}
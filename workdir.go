@@ -0,0 +1,48 @@
+package main
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// isTemplated reports whether a dir uses template variables, like
+// {{.USER_WORKING_DIR}}, which only task can resolve
+func isTemplated(dir string) bool {
+	return strings.Contains(dir, "{{")
+}
+
+// includedWorkDir returns the directory a task of an included Taskfile runs
+// in, relative to the including Taskfile's directory. Like task, included
+// tasks run in the including Taskfile's directory unless the include sets a
+// dir, which the task's own dir is then relative to.
+func includedWorkDir(includeDir, dir string) string {
+	if filepath.IsAbs(dir) || isTemplated(dir) || includeDir == "" {
+		return dir
+	}
+	if isTemplated(includeDir) {
+		return includeDir
+	}
+	return filepath.Join(includeDir, dir)
+}
+
+// resolveWorkDir returns the absolute directory a task runs in, given the
+// directory of the Taskfile task was started with. A templated dir is
+// returned as written.
+func resolveWorkDir(root, dir string) string {
+	if filepath.IsAbs(dir) || isTemplated(dir) {
+		return dir
+	}
+	return filepath.Join(root, dir)
+}
+
+// workDirDetails renders the directory the task runs in for the details
+// view, noting when the task doesn't set a dir of its own
+func workDirDetails(task Task) string {
+	if task.WorkDir == "" {
+		return ""
+	}
+	if task.Dir == "" {
+		return "\n    dir: " + task.WorkDir + " (inherits)"
+	}
+	return "\n    dir: " + task.WorkDir
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// yamlContextLines is how many lines are shown around a YAML error
+const yamlContextLines = 2
+
+// yamlLine finds the line number in yaml.v3 error messages
+var yamlLine = regexp.MustCompile(`line (\d+): `)
+
+// taskfileError is a YAML error in a Taskfile, with the lines around it
+type taskfileError struct {
+	path    string
+	line    int // 0 when the YAML library gave no line
+	err     error
+	context string
+}
+
+func (e *taskfileError) Error() string {
+	if e.line == 0 {
+		return fmt.Sprintf("%s: %v", e.path, e.err)
+	}
+	// The line is already part of the location
+	message := strings.Replace(e.err.Error(), "line "+strconv.Itoa(e.line)+": ", "", 1)
+	return fmt.Sprintf("%s:%d: %s\n\n%s", e.path, e.line, message, e.context)
+}
+
+func (e *taskfileError) Unwrap() error {
+	return e.err
+}
+
+// newTaskfileError wraps a YAML error in the Taskfile at path, adding the
+// lines of data around the line the error mentions
+func newTaskfileError(path string, data []byte, err error) error {
+	match := yamlLine.FindStringSubmatch(err.Error())
+	if match == nil {
+		return &taskfileError{path: path, err: err}
+	}
+	line, _ := strconv.Atoi(match[1])
+	return &taskfileError{path: path, line: line, err: err, context: yamlContext(data, line)}
+}
+
+// yamlContext renders the lines around line, marking the line itself
+func yamlContext(data []byte, line int) string {
+	lines := strings.Split(strings.TrimRight(string(data), "\n"), "\n")
+	first := max(line-yamlContextLines, 1)
+	last := min(line+yamlContextLines, len(lines))
+	if first > last {
+		return ""
+	}
+	width := len(strconv.Itoa(last))
+
+	var b strings.Builder
+	for n := first; n <= last; n++ {
+		marker := " "
+		if n == line {
+			marker = ">"
+		}
+		fmt.Fprintf(&b, "%s %*d | %s\n", marker, width, n, lines[n-1])
+	}
+	return strings.TrimRight(b.String(), "\n")
+}